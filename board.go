@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+const (
+	// 默认的进出港航班刷新间隔
+	defaultBoardFollowInterval = 30 * time.Second
+	// 连续刷新失败时最多延长到刷新间隔的倍数
+	boardFollowMaxIntervalFactor = 8
+	// 清屏并把光标移到左上角
+	clearScreenSequence string = "\033[H\033[2J"
+)
+
+// 机场进出港的时间范围（按北京时间计算; window 为日期当天的时间段, 开始时间晚于结束时间时结束于第二天; next 为从现在开始的时长）
+func airportBoardTimeRange(date, window string, next time.Duration, now time.Time) (since, until time.Time, err error) {
+	switch {
+	case next < 0:
+		return since, until, flightgo.NewInvalidArgumentError("next 不能为负数")
+	case next > 0 && window != "":
+		return since, until, flightgo.NewInvalidArgumentError("next 和 window 不能同时使用")
+	case next > 0:
+		return now, now.Add(next), nil
+	case window == "":
+		return since, until, nil
+	}
+	clockWindow, err := flightgo.ParseClockWindow(window)
+	if err != nil {
+		return since, until, err
+	}
+	now = now.In(flightgo.ChinaLocation)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, flightgo.ChinaLocation)
+	if date != "" {
+		if day, err = time.ParseInLocation(flightgo.AirportBoardDateLayout, date, flightgo.ChinaLocation); err != nil {
+			return since, until, flightgo.NewInvalidArgumentError("日期格式错误（需要 YYYY-MM-DD）: %s", date)
+		}
+	}
+	since = day.Add(time.Duration(clockWindow.From) * time.Minute)
+	until = day.Add(time.Duration(clockWindow.To) * time.Minute)
+	if clockWindow.From > clockWindow.To {
+		until = until.Add(24 * time.Hour)
+	}
+	return since, until, nil
+}
+
+// 根据命令行参数生成机场进出港查询条件
+func airportBoardRequestFromFlags(airport, direction string, now time.Time) (flightgo.AirportBoardRequest, error) {
+	since, until, err := airportBoardTimeRange(airportDate, airportWindow, airportNext, now)
+	return flightgo.AirportBoardRequest{
+		Airport:   airport,
+		Direction: direction,
+		Date:      airportDate,
+		Since:     since,
+		Until:     until,
+	}, err
+}
+
+// 以逗号分隔的参数值（忽略空值）
+func splitFlagValues(value string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// 根据命令行参数生成进出港航班过滤条件
+func boardFilterFromFlags() flightgo.BoardFilter {
+	return flightgo.BoardFilter{
+		Airlines: splitFlagValues(boardAirlines),
+		City:     boardCity,
+		Statuses: splitFlagValues(boardStatuses),
+		Aircraft: boardAircraft,
+	}
+}
+
+// 与上次查询相比状态或实际/预计时间发生变化的航班（新出现的航班不算变化）
+func changedBoardEntries(previous map[string]flightgo.BoardEntry, entries []flightgo.BoardEntry) map[string]bool {
+	changed := make(map[string]bool)
+	for _, entry := range entries {
+		last, ok := previous[entry.Key()]
+		if !ok {
+			continue
+		}
+		if last.StatusCode != entry.StatusCode || last.Status != entry.Status ||
+			!last.ActualTime.Equal(entry.ActualTime.Time) || !last.EstimatedTime.Equal(entry.EstimatedTime.Time) {
+			changed[entry.Key()] = true
+		}
+	}
+	return changed
+}
+
+// 持续刷新进出港航班（每次刷新清屏后重绘表格, 高亮状态或实际/预计时间发生变化的航班）
+// newRequest 在每次刷新时生成查询条件（-next 的时间范围随当前时间变化）
+func runAirportFollow(ctx context.Context, client *flightgo.Client, newRequest func(now time.Time) (flightgo.AirportBoardRequest, error),
+	filter flightgo.BoardFilter, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultBoardFollowInterval
+	}
+	var previous map[string]flightgo.BoardEntry
+	failures := 0
+	for {
+		req, err := newRequest(time.Now())
+		if err != nil {
+			return err
+		}
+		entries, err := client.AirportBoard(ctx, req)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && previous == nil:
+			// 第一次查询失败时直接返回（例如城市名错误）
+			return err
+		case err != nil:
+			failures++
+			logger.Errorf("[Flight-Go]刷新机场 %s 进出港航班失败, 错误原因: %v", req.Airport, err)
+			fmt.Printf("[%s] 刷新失败: %v\n", time.Now().Format("15:04:05"), err)
+		default:
+			failures = 0
+			recordBoardHistory(entries)
+			entries = filter.Apply(entries)
+			changed := changedBoardEntries(previous, entries)
+			directionName := "出港"
+			if req.Direction == flightgo.DirectionArrival {
+				directionName = "进港"
+			}
+			fmt.Print(clearScreenSequence)
+			fmt.Printf("%s %s航班: 共 %d 个, %d 个有变化（已高亮）; 更新于 %s, 每 %s 刷新, Ctrl+C 退出\n",
+				req.Airport, directionName, len(entries), len(changed), time.Now().Format("15:04:05"), interval)
+			renderAirportInfoTable(req.Direction, entries, changed)
+			previous = make(map[string]flightgo.BoardEntry, len(entries))
+			for _, entry := range entries {
+				previous[entry.Key()] = entry
+			}
+		}
+		if sleepWithContext(ctx, nextWatchInterval(interval, interval*boardFollowMaxIntervalFactor, failures)) != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+const (
+	// 实际时间晚于计划时间不超过该时长视为准点
+	boardOnTimeThreshold = 15 * time.Minute
+	// 默认展示的准点率最低的航线数量
+	defaultBoardStatsTopRoutes int = 5
+)
+
+// 准点率统计的分组
+const (
+	BoardStatsGroupAirport string = "airport"
+	BoardStatsGroupAirline string = "airline"
+	BoardStatsGroupRoute   string = "route"
+)
+
+// 某个机场、航空公司或航线的准点率统计
+type BoardStats struct {
+	// 分组（airport: 机场; airline: 航空公司; route: 航线）
+	Group string `json:"group"`
+	Name  string `json:"name"`
+	// 航班数（多次查询中的同一航班只计算一次）
+	Flights int `json:"flights"`
+	// 已起飞（出港）或已到达（进港）的航班数
+	Completed int `json:"completed"`
+	OnTime    int `json:"onTime"`
+	// 准点率（准点航班数 / 已起飞或到达和取消的航班数, 取消的航班视为不准点, 百分比）
+	OnTimePercent float64 `json:"onTimePercent"`
+	// 平均延误和 90% 分位延误（分钟, 早于计划时间按 0 计算）
+	AverageDelay float64 `json:"averageDelay"`
+	P90Delay     int64   `json:"p90Delay"`
+	Cancelled    int     `json:"cancelled"`
+}
+
+// 准点率统计结果
+type BoardStatsReport struct {
+	// 统计的查询次数和航班数（去重后）
+	Polls   int
+	Flights int
+	// 最早和最晚的计划时间
+	From     time.Time
+	To       time.Time
+	Airports []BoardStats
+	Airlines []BoardStats
+	// 准点率最低的航线
+	WorstRoutes []BoardStats
+}
+
+// 全部统计（机场、航空公司、航线依次排列）
+func (r BoardStatsReport) All() []BoardStats {
+	all := make([]BoardStats, 0, len(r.Airports)+len(r.Airlines)+len(r.WorstRoutes))
+	all = append(all, r.Airports...)
+	all = append(all, r.Airlines...)
+	return append(all, r.WorstRoutes...)
+}
+
+// 合并多次查询的进出港航班（同一航班取最后一次观测, 观测记录需按观测时间排序）
+func latestBoardEntries(observations []BoardObservation) (entries []flightgo.BoardEntry, polls int) {
+	latest := make(map[string]int)
+	observedAt := make(map[time.Time]bool)
+	for _, observation := range observations {
+		observedAt[observation.ObservedAt] = true
+		key := observation.Key()
+		if i, ok := latest[key]; ok {
+			entries[i] = observation.BoardEntry
+			continue
+		}
+		latest[key] = len(entries)
+		entries = append(entries, observation.BoardEntry)
+	}
+	return entries, len(observedAt)
+}
+
+// 航空公司代码（航班号前两位）
+func boardAirlineCode(entry flightgo.BoardEntry) string {
+	flightNumber := strings.ToUpper(strings.TrimSpace(entry.FlightNumber))
+	if len(flightNumber) < 2 {
+		return flightNumber
+	}
+	return flightNumber[:2]
+}
+
+// 机场展示名称（例如: 广州白云(CAN)）
+func boardAirportName(airport flightgo.Airport) string {
+	name := airport.Name
+	if !strings.HasPrefix(name, airport.CityName) {
+		name = airport.CityName + name
+	}
+	if airport.IATA != "" {
+		name += "(" + airport.IATA + ")"
+	}
+	return name
+}
+
+// 航线展示名称（例如: 广州白云 → 上海虹桥）
+func boardRouteName(entry flightgo.BoardEntry) string {
+	name := func(airport flightgo.Airport) string {
+		if strings.HasPrefix(airport.Name, airport.CityName) {
+			return airport.Name
+		}
+		return airport.CityName + airport.Name
+	}
+	return trackLegName(name(entry.Departure), name(entry.Arrival))
+}
+
+// 某个分组的航班汇总
+type boardStatsAccumulator struct {
+	stats  BoardStats
+	delays []time.Duration
+}
+
+func (a *boardStatsAccumulator) add(entry flightgo.BoardEntry) {
+	a.stats.Flights++
+	switch {
+	case entry.Phase == flightgo.FlightPhaseCancelled:
+		a.stats.Cancelled++
+	case !entry.ActualTime.IsZero() && !entry.ScheduledTime.IsZero():
+		delay := entry.ActualTime.Sub(entry.ScheduledTime.Time)
+		if delay < 0 {
+			delay = 0
+		}
+		if delay <= boardOnTimeThreshold {
+			a.stats.OnTime++
+		}
+		a.delays = append(a.delays, delay)
+	}
+}
+
+func (a *boardStatsAccumulator) result() BoardStats {
+	stats := a.stats
+	stats.Completed = len(a.delays)
+	if stats.Completed+stats.Cancelled > 0 {
+		stats.OnTimePercent = math.Round(float64(stats.OnTime)/float64(stats.Completed+stats.Cancelled)*1000) / 10
+	}
+	if stats.Completed == 0 {
+		return stats
+	}
+	sort.Slice(a.delays, func(i, j int) bool {
+		return a.delays[i] < a.delays[j]
+	})
+	var total time.Duration
+	for _, delay := range a.delays {
+		total += delay
+	}
+	stats.AverageDelay = math.Round(total.Minutes()/float64(stats.Completed)*10) / 10
+	// 最近秩法计算分位数
+	rank := int(math.Ceil(float64(len(a.delays))*0.9)) - 1
+	stats.P90Delay = int64(a.delays[rank] / time.Minute)
+	return stats
+}
+
+// 按分组汇总航班
+func groupBoardStats(group string, entries []flightgo.BoardEntry, name func(flightgo.BoardEntry) string) []BoardStats {
+	accumulators := make(map[string]*boardStatsAccumulator)
+	for _, entry := range entries {
+		key := name(entry)
+		accumulator, ok := accumulators[key]
+		if !ok {
+			accumulator = &boardStatsAccumulator{stats: BoardStats{Group: group, Name: key}}
+			accumulators[key] = accumulator
+		}
+		accumulator.add(entry)
+	}
+	stats := make([]BoardStats, 0, len(accumulators))
+	for _, accumulator := range accumulators {
+		stats = append(stats, accumulator.result())
+	}
+	return stats
+}
+
+// 是否有已执行或取消的航班（没有时准点率没有意义）
+func (s BoardStats) hasResult() bool {
+	return s.Completed+s.Cancelled > 0
+}
+
+// 按准点率从高到低排序（没有已执行或取消航班的排在最后; 准点率相同时平均延误短的在前）
+func sortBoardStatsByReliability(stats []BoardStats) {
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		switch {
+		case a.hasResult() != b.hasResult():
+			return a.hasResult()
+		case a.OnTimePercent != b.OnTimePercent:
+			return a.OnTimePercent > b.OnTimePercent
+		case a.AverageDelay != b.AverageDelay:
+			return a.AverageDelay < b.AverageDelay
+		}
+		return a.Name < b.Name
+	})
+}
+
+// 统计进出港航班的准点率（topRoutes 为展示的准点率最低的航线数量）
+func summarizeBoardStats(entries []flightgo.BoardEntry, polls, topRoutes int) BoardStatsReport {
+	report := BoardStatsReport{Polls: polls, Flights: len(entries)}
+	for _, entry := range entries {
+		if report.From.IsZero() || entry.ScheduledTime.Before(report.From) {
+			report.From = entry.ScheduledTime.Time
+		}
+		if entry.ScheduledTime.After(report.To) {
+			report.To = entry.ScheduledTime.Time
+		}
+	}
+	report.Airports = groupBoardStats(BoardStatsGroupAirport, entries, func(entry flightgo.BoardEntry) string {
+		return boardAirportName(entry.LocalEnd())
+	})
+	sortBoardStatsByReliability(report.Airports)
+	report.Airlines = groupBoardStats(BoardStatsGroupAirline, entries, boardAirlineCode)
+	sortBoardStatsByReliability(report.Airlines)
+	// 只统计有已执行或取消航班的航线, 按准点率从低到高排列
+	routes := make([]BoardStats, 0)
+	for _, route := range groupBoardStats(BoardStatsGroupRoute, entries, boardRouteName) {
+		if route.hasResult() {
+			routes = append(routes, route)
+		}
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i], routes[j]
+		switch {
+		case a.OnTimePercent != b.OnTimePercent:
+			return a.OnTimePercent < b.OnTimePercent
+		case a.AverageDelay != b.AverageDelay:
+			return a.AverageDelay > b.AverageDelay
+		}
+		return a.Name < b.Name
+	})
+	if topRoutes > 0 && len(routes) > topRoutes {
+		routes = routes[:topRoutes]
+	}
+	report.WorstRoutes = routes
+	return report
+}
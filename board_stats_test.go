@@ -0,0 +1,145 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+var (
+	testBaiyun   = flightgo.Airport{CityName: "广州", Name: "白云国际机场", IATA: "CAN"}
+	testHongqiao = flightgo.Airport{CityName: "上海", Name: "虹桥国际机场"}
+	testCapital  = flightgo.Airport{CityName: "北京", Name: "首都国际机场"}
+)
+
+// 广州白云出港航班（delay 为实际起飞时间晚于计划的分钟数, 小于 0 表示还没有起飞）
+func testBoardEntry(flightNumber string, arrival flightgo.Airport, hour, delay int) flightgo.BoardEntry {
+	scheduled := time.Date(2019, 11, 15, hour, 0, 0, 0, testLocation)
+	entry := flightgo.BoardEntry{
+		Direction:     flightgo.DirectionDeparture,
+		FlightNumber:  flightNumber,
+		Phase:         flightgo.FlightPhaseScheduled,
+		Departure:     testBaiyun,
+		Arrival:       arrival,
+		ScheduledTime: flightgo.DateTime{Time: scheduled},
+	}
+	if delay >= 0 {
+		entry.Phase = flightgo.FlightPhaseDeparted
+		entry.ActualTime = flightgo.DateTime{Time: scheduled.Add(time.Duration(delay) * time.Minute)}
+	}
+	return entry
+}
+
+func TestLatestBoardEntries(t *testing.T) {
+	first, second := testObservedAt(8), testObservedAt(9)
+	scheduled := testBoardEntry("CZ3539", testHongqiao, 9, -1)
+	departed := testBoardEntry("CZ3539", testHongqiao, 9, 40)
+	observations := []BoardObservation{
+		{ObservedAt: first, BoardEntry: scheduled},
+		{ObservedAt: first, BoardEntry: testBoardEntry("CZ3101", testCapital, 8, 5)},
+		{ObservedAt: second, BoardEntry: departed},
+	}
+	entries, polls := latestBoardEntries(observations)
+	if polls != 2 || len(entries) != 2 {
+		t.Fatalf("查询次数和航班数: %d %d, 期望 2 2", polls, len(entries))
+	}
+	// 同一航班取最后一次观测, 保留第一次出现的顺序
+	if entries[0].FlightNumber != "CZ3539" || entries[0].ActualTime.IsZero() || entries[1].FlightNumber != "CZ3101" {
+		t.Errorf("航班: %+v", entries)
+	}
+}
+
+func TestBoardNames(t *testing.T) {
+	entry := testBoardEntry("cz3539", testHongqiao, 9, -1)
+	if name := boardAirlineCode(entry); name != "CZ" {
+		t.Errorf("航空公司代码: %s", name)
+	}
+	if name := boardAirportName(testBaiyun); name != "广州白云国际机场(CAN)" {
+		t.Errorf("机场名称: %s", name)
+	}
+	if name := boardAirportName(flightgo.Airport{CityName: "上海", Name: "上海虹桥"}); name != "上海虹桥" {
+		t.Errorf("机场名称: %s", name)
+	}
+	if name := boardRouteName(entry); name != "广州白云国际机场 → 上海虹桥国际机场" {
+		t.Errorf("航线名称: %s", name)
+	}
+}
+
+func TestSummarizeBoardStats(t *testing.T) {
+	cancelled := testBoardEntry("MU5301", testHongqiao, 12, -1)
+	cancelled.Phase, cancelled.Status = flightgo.FlightPhaseCancelled, "取消"
+	entries := []flightgo.BoardEntry{
+		testBoardEntry("CZ3101", testCapital, 8, 5),
+		testBoardEntry("CZ3103", testCapital, 9, 0),
+		testBoardEntry("CZ3539", testHongqiao, 10, 40),
+		// 早于计划时间起飞按延误 0 分钟计算
+		testBoardEntry("CZ3541", testHongqiao, 11, -1),
+		cancelled,
+		testBoardEntry("MU5303", testHongqiao, 13, 20),
+	}
+	entries[3].ActualTime = flightgo.DateTime{Time: entries[3].ScheduledTime.Add(-5 * time.Minute)}
+	report := summarizeBoardStats(entries, 3, 1)
+	if report.Polls != 3 || report.Flights != 6 || report.From.Hour() != 8 || report.To.Hour() != 13 {
+		t.Errorf("统计范围: %+v", report)
+	}
+	tests := []struct {
+		name  string
+		stats []BoardStats
+		want  []BoardStats
+	}{
+		{"机场", report.Airports, []BoardStats{
+			{Group: BoardStatsGroupAirport, Name: "广州白云国际机场(CAN)", Flights: 6, Completed: 5, OnTime: 3, OnTimePercent: 50, AverageDelay: 13, P90Delay: 40, Cancelled: 1},
+		}},
+		// 按准点率从高到低排序
+		{"航空公司", report.Airlines, []BoardStats{
+			{Group: BoardStatsGroupAirline, Name: "CZ", Flights: 4, Completed: 4, OnTime: 3, OnTimePercent: 75, AverageDelay: 11.3, P90Delay: 40},
+			{Group: BoardStatsGroupAirline, Name: "MU", Flights: 2, Completed: 1, OnTimePercent: 0, AverageDelay: 20, P90Delay: 20, Cancelled: 1},
+		}},
+		// 只保留准点率最低的一条航线
+		{"航线", report.WorstRoutes, []BoardStats{
+			{Group: BoardStatsGroupRoute, Name: "广州白云国际机场 → 上海虹桥国际机场", Flights: 4, Completed: 3, OnTime: 1, OnTimePercent: 25, AverageDelay: 20, P90Delay: 40, Cancelled: 1},
+		}},
+	}
+	for _, tt := range tests {
+		if len(tt.stats) != len(tt.want) {
+			t.Errorf("%s: %+v", tt.name, tt.stats)
+			continue
+		}
+		for i := range tt.want {
+			if tt.stats[i] != tt.want[i] {
+				t.Errorf("%s 第 %d 条: %+v, 期望 %+v", tt.name, i+1, tt.stats[i], tt.want[i])
+			}
+		}
+	}
+	if all := report.All(); len(all) != 4 || all[0].Group != BoardStatsGroupAirport || all[3].Group != BoardStatsGroupRoute {
+		t.Errorf("全部统计: %+v", all)
+	}
+}
+
+func TestHistoryStoreBoard(t *testing.T) {
+	store := NewHistoryStore(t.TempDir())
+	arrival := testBoardEntry("CA1315", testBaiyun, 10, 0)
+	arrival.Direction, arrival.Departure = flightgo.DirectionArrival, testCapital
+	entries := []flightgo.BoardEntry{testBoardEntry("CZ3101", testCapital, 8, 5), testBoardEntry("CZ3539", testHongqiao, 10, -1), arrival}
+	if err := store.AppendBoard(boardObservations(entries, testObservedAt(9))); err != nil {
+		t.Fatalf("写入进出港航班失败: %v", err)
+	}
+	tests := []struct {
+		name   string
+		filter BoardHistoryFilter
+		count  int
+	}{
+		{"不过滤", BoardHistoryFilter{}, 3},
+		{"按机场", BoardHistoryFilter{Airports: []flightgo.Airport{{IATA: "can"}}}, 3},
+		{"其他机场", BoardHistoryFilter{Airports: []flightgo.Airport{{IATA: "PEK"}}}, 0},
+		{"按类别", BoardHistoryFilter{Direction: flightgo.DirectionArrival}, 1},
+		{"按计划时间", BoardHistoryFilter{Since: time.Date(2019, 11, 15, 9, 0, 0, 0, testLocation)}, 2},
+	}
+	for _, tt := range tests {
+		observations, err := store.QueryBoard(tt.filter)
+		if err != nil || len(observations) != tt.count {
+			t.Errorf("%s: %d 条（%v）, 期望 %d", tt.name, len(observations), err, tt.count)
+		}
+	}
+}
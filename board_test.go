@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+func TestAirportBoardTimeRange(t *testing.T) {
+	now := time.Date(2019, 11, 15, 9, 30, 0, 0, flightgo.ChinaLocation)
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2019, 11, day, hour, min, 0, 0, flightgo.ChinaLocation)
+	}
+	tests := []struct {
+		name   string
+		date   string
+		window string
+		next   time.Duration
+		since  time.Time
+		until  time.Time
+		valid  bool
+	}{
+		{"不限制", "", "", 0, time.Time{}, time.Time{}, true},
+		{"当天的时间段", "", "08:00-12:00", 0, at(15, 8, 0), at(15, 12, 0), true},
+		{"指定日期", "2019-11-16", "08:00-12:00", 0, at(16, 8, 0), at(16, 12, 0), true},
+		{"跨天的时间段", "2019-11-16", "22:00-02:00", 0, at(16, 22, 0), at(17, 2, 0), true},
+		{"从现在开始", "", "", 2 * time.Hour, at(15, 9, 30), at(15, 11, 30), true},
+		{"next 为负数", "", "", -time.Hour, time.Time{}, time.Time{}, false},
+		{"next 和 window 同时使用", "", "08:00-12:00", time.Hour, time.Time{}, time.Time{}, false},
+		{"时间段格式错误", "", "8点-12点", 0, time.Time{}, time.Time{}, false},
+		{"日期格式错误", "20191116", "08:00-12:00", 0, time.Time{}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		since, until, err := airportBoardTimeRange(tt.date, tt.window, tt.next, now)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: %v, 期望合法: %v", tt.name, err, tt.valid)
+			continue
+		}
+		if !since.Equal(tt.since) || !until.Equal(tt.until) {
+			t.Errorf("%s: %s ~ %s, 期望 %s ~ %s", tt.name, since, until, tt.since, tt.until)
+		}
+	}
+}
+
+func TestSplitFlagValues(t *testing.T) {
+	tests := []struct {
+		value  string
+		values string
+	}{
+		{"", ""},
+		{"CZ", "CZ"},
+		{" CZ, MU ,,HU ", "CZ|MU|HU"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(splitFlagValues(tt.value), "|"); got != tt.values {
+			t.Errorf("splitFlagValues(%q) = %s, 期望 %s", tt.value, got, tt.values)
+		}
+	}
+}
+
+func TestChangedBoardEntries(t *testing.T) {
+	scheduled := flightgo.DateTime{Time: time.Date(2019, 11, 15, 9, 0, 0, 0, flightgo.ChinaLocation)}
+	entry := flightgo.BoardEntry{Direction: flightgo.DirectionDeparture, FlightNumber: "CZ3539", Status: "计划", ScheduledTime: scheduled}
+	delayed := entry
+	delayed.StatusCode, delayed.Status = flightgo.FlightStatusDelayed, "延误"
+	estimated := entry
+	estimated.EstimatedTime = flightgo.DateTime{Time: scheduled.Add(40 * time.Minute)}
+	tests := []struct {
+		name     string
+		previous []flightgo.BoardEntry
+		current  flightgo.BoardEntry
+		changed  bool
+	}{
+		{"第一次查询", nil, entry, false},
+		{"新出现的航班", []flightgo.BoardEntry{{FlightNumber: "CZ3101"}}, entry, false},
+		{"没有变化", []flightgo.BoardEntry{entry}, entry, false},
+		{"状态变化", []flightgo.BoardEntry{entry}, delayed, true},
+		{"预计时间变化", []flightgo.BoardEntry{entry}, estimated, true},
+	}
+	for _, tt := range tests {
+		var previous map[string]flightgo.BoardEntry
+		if tt.previous != nil {
+			previous = make(map[string]flightgo.BoardEntry)
+			for _, entry := range tt.previous {
+				previous[entry.Key()] = entry
+			}
+		}
+		changed := changedBoardEntries(previous, []flightgo.BoardEntry{tt.current})
+		if changed[tt.current.Key()] != tt.changed || len(changed) > 1 {
+			t.Errorf("%s: %v, 期望 %v", tt.name, changed, tt.changed)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+// 录制/回放模式
+const (
+	CassetteRecord string = "record"
+	CassetteReplay string = "replay"
+)
+
+// 录制的请求
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// 录制的响应
+type CassetteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// 一次请求和响应的记录
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// 录制/回放 HTTP 请求的 Transport
+// 每次请求保存为 cassette 目录下的一个文件, 文件名由请求方法、地址和请求体的摘要以及该请求的序号组成,
+// 回放时相同的请求按录制顺序返回, 超出录制次数时返回最后一次的响应
+type CassetteTransport struct {
+	Dir       string
+	Mode      string
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	sequence map[string]int
+}
+
+func NewCassetteTransport(dir, mode string) *CassetteTransport {
+	return &CassetteTransport{
+		Dir:       dir,
+		Mode:      mode,
+		Transport: http.DefaultTransport,
+		sequence:  make(map[string]int),
+	}
+}
+
+// 请求摘要
+func (t *CassetteTransport) requestKey(method, url, body string) string {
+	digest := sha1.Sum([]byte(method + " " + url + "\n" + body))
+	return hex.EncodeToString(digest[:])[:16]
+}
+
+// 当前请求的录制文件路径（序号从 1 开始）
+func (t *CassetteTransport) nextInteractionPath(key string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sequence[key]++
+	return filepath.Join(t.Dir, fmt.Sprintf("%s-%03d.json", key, t.sequence[key]))
+}
+
+// 回放时的文件路径（超出录制次数时使用最后一次）
+func (t *CassetteTransport) replayInteractionPath(key string) string {
+	path := t.nextInteractionPath(key)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.sequence[key] > 1 {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		t.sequence[key]--
+		path = filepath.Join(t.Dir, fmt.Sprintf("%s-%03d.json", key, t.sequence[key]))
+	}
+	return path
+}
+
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	key := t.requestKey(req.Method, req.URL.String(), string(body))
+	if t.Mode == CassetteReplay {
+		return t.replay(req, key)
+	}
+	return t.record(req, key, body)
+}
+
+// 回放
+func (t *CassetteTransport) replay(req *http.Request, key string) (*http.Response, error) {
+	path := t.replayInteractionPath(key)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette 中没有对应的请求记录: %s %s", req.Method, req.URL.String())
+	}
+	interaction := &CassetteInteraction{}
+	if err := json.Unmarshal(data, interaction); err != nil {
+		return nil, fmt.Errorf("cassette 文件格式错误: %s, %v", path, err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// 录制
+func (t *CassetteTransport) record(req *http.Request, key string, body []byte) (*http.Response, error) {
+	resp, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	reader := io.Reader(resp.Body)
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		if reader, err = gzip.NewReader(resp.Body); err != nil {
+			return nil, err
+		}
+	}
+	respBody, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	// 保存解压后的响应体, 并去掉相关的头避免重复解压
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = int64(len(respBody))
+	resp.Uncompressed = true
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	interaction := CassetteInteraction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header,
+			Body:   string(body),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       string(respBody),
+		},
+	}
+	data := &bytes.Buffer{}
+	encoder := json.NewEncoder(data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(interaction); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(t.nextInteractionPath(key), data.Bytes(), 0644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// 录制/回放目录（命令行 -record / -replay 参数）
+var (
+	cassetteRecordDir string
+	cassetteReplayDir string
+	cassetteTransport *CassetteTransport
+)
+
+// 根据命令行参数初始化录制/回放（所有 HTTP 客户端共用一个 Transport, 保证请求序号连续）
+func configureCassette() error {
+	switch {
+	case cassetteRecordDir != "" && cassetteReplayDir != "":
+		return flightgo.NewInvalidArgumentError("-record 和 -replay 不能同时使用")
+	case cassetteRecordDir != "":
+		cassetteTransport = NewCassetteTransport(cassetteRecordDir, CassetteRecord)
+	case cassetteReplayDir != "":
+		if _, err := os.Stat(cassetteReplayDir); err != nil {
+			return flightgo.NewInvalidArgumentError("cassette 目录不存在: %s", cassetteReplayDir)
+		}
+		cassetteTransport = NewCassetteTransport(cassetteReplayDir, CassetteReplay)
+	}
+	return nil
+}
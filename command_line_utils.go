@@ -1,8 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
 )
 
 type FlightCommand struct {
@@ -21,6 +30,11 @@ var (
 	flightArrivalCityName   string
 	flightDate              string
 	flightTripType          string
+	flightTableProvider     string
+	flightTableMultiCity    bool
+	flightTableFlexDays     int
+	flightTableUntilDate    string
+	flightTableAllFares     bool
 )
 
 var (
@@ -29,56 +43,630 @@ var (
 	flightOverSeaArrivalCityName   string
 	flightOverSeaDate              string
 	flightOverSeaCabinType         string
+	flightOverSeaProvider          string
+	flightOverSeaInterval          time.Duration
+	flightOverSeaTimeout           time.Duration
+)
+
+// 航班过滤条件（schedule 和 oversea 命令共用）
+var (
+	filterAirlines       string
+	filterDepartWindow   string
+	filterArriveWindow   string
+	filterAircraft       string
+	filterMeal           bool
+	filterMinPunctuality float64
+	filterMaxPrice       int64
+	filterMaxStops       int
+	filterMaxLayover     time.Duration
+	resultSortOrder      string
 )
 
 var (
 	flightNumberInfoCommand = &FlightCommand{UsageLine: "code"}
 	flightNumber            string
 	flightNumberCheckDate   string
+	flightNumberProvider    string
+	flightNumberTrack       bool
+	flightNumberInterval    time.Duration
 )
 
 var (
-	airportInfoCommand = &FlightCommand{UsageLine: "airport"}
-	airportName        string
-	airportDepOrArr    string
+	airportInfoCommand  = &FlightCommand{UsageLine: "airport"}
+	airportName         string
+	airportDepOrArr     string
+	airportInfoProvider string
+	airportDate         string
+	airportWindow       string
+	airportNext         time.Duration
+	airportFollow       bool
+	airportInterval     time.Duration
+	airportStatsSince   string
+	airportStatsUntil   string
+	airportStatsOffline bool
+	airportStatsTop     int
+	boardAirlines       string
+	boardCity           string
+	boardStatuses       string
+	boardAircraft       string
+)
+
+var citiesCommand = &FlightCommand{UsageLine: "cities"}
+
+var statusCodesCommand = &FlightCommand{UsageLine: "status-codes"}
+
+var (
+	historyCommand      = &FlightCommand{UsageLine: "history"}
+	historyFlightNumber string
+	historyCabinName    string
+	historySince        string
+	historyUntil        string
+	historyLimit        int
+	historySummary      bool
 )
 
+var (
+	watchCommand   = &FlightCommand{UsageLine: "watch"}
+	watchOnce      bool
+	watchStatePath string
+	watchProvider  string
+)
+
+var (
+	serveCommand  = &FlightCommand{UsageLine: "serve"}
+	serveTimeout  time.Duration
+	serveProvider string
+)
+
+// 输出格式（所有命令共用）
+var outputFormat string
+
 var flightCommands = []*FlightCommand{
 	flightTableCommand,
 	flightNumberInfoCommand,
 	airportInfoCommand,
 	flightOverSeaTableCommand,
+	citiesCommand,
+	statusCodesCommand,
+	watchCommand,
+	historyCommand,
+	serveCommand,
+}
+
+// 输出错误信息, 返回对应的退出码
+func reportError(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+	logger.Debugf("[Flight-Go]%+v", err)
+	fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+	return exitCodeOf(err)
+}
+
+// 检查命令参数数量
+func checkArgCount(command string, args []string, min int) error {
+	if len(args) < min {
+		return flightgo.NewInvalidArgumentError("%s 命令参数不足（需要 %d 个, 实际 %d 个）", command, min, len(args))
+	}
+	return nil
+}
+
+// 根据命令行参数生成航班过滤条件
+func itineraryFilterFromFlags() (flightgo.ItineraryFilter, error) {
+	filter := flightgo.ItineraryFilter{
+		Aircraft:       filterAircraft,
+		RequireMeal:    filterMeal,
+		MinPunctuality: filterMinPunctuality,
+		MaxPrice:       filterMaxPrice,
+		DirectOnly:     filterMaxStops == 0,
+		MaxLayover:     int64(filterMaxLayover / time.Minute),
+	}
+	if filterAirlines != "" {
+		filter.Airlines = strings.Split(filterAirlines, ",")
+	}
+	if filterMaxStops > 0 {
+		filter.MaxStops = filterMaxStops
+	}
+	var err error
+	if filterDepartWindow != "" {
+		if filter.DepartureWindow, err = flightgo.ParseClockWindow(filterDepartWindow); err != nil {
+			return filter, err
+		}
+	}
+	if filterArriveWindow != "" {
+		if filter.ArrivalWindow, err = flightgo.ParseClockWindow(filterArriveWindow); err != nil {
+			return filter, err
+		}
+	}
+	if filterMinPunctuality < 0 || filterMaxPrice < 0 || filterMaxLayover < 0 {
+		return filter, flightgo.NewInvalidArgumentError("-min-punctuality、-max-price 和 -max-layover 不能为负数")
+	}
+	return filter, nil
+}
+
+// 根据命令行参数生成排序方式（未指定时保持接口返回的顺序）
+func itinerarySortFromFlags() (flightgo.ItinerarySort, error) {
+	if resultSortOrder == "" {
+		return flightgo.ItinerarySort{}, nil
+	}
+	return flightgo.ParseItinerarySort(resultSortOrder)
 }
 
-// 查询机场信息
+// 查询价格历史（参数: <出发地> <到达地> [出发日期]）
+func executeHistoryFunc(args []string) int {
+	if err := checkArgCount("history", args, 2); err != nil {
+		return reportError(err)
+	}
+	since, err := parseHistoryDate("-since", historySince)
+	if err != nil {
+		return reportError(err)
+	}
+	until, err := parseHistoryDate("-until", historyUntil)
+	if err != nil {
+		return reportError(err)
+	}
+	if !until.IsZero() {
+		// 包含结束日期当天
+		until = until.AddDate(0, 0, 1)
+	}
+	filter := FareHistoryFilter{
+		Departure:    args[0],
+		Arrival:      args[1],
+		FlightNumber: historyFlightNumber,
+		CabinName:    historyCabinName,
+		Since:        since,
+		Until:        until,
+	}
+	if len(args) > 2 {
+		filter.Date = args[2]
+	}
+	observations, err := NewHistoryStore(defaultHistoryDir()).QueryFares(filter)
+	if err != nil {
+		return reportError(err)
+	}
+	if historySummary {
+		summaries := summarizeFareHistory(observations)
+		if outputFormat == OutputTable {
+			renderFareHistorySummaryTable(summaries)
+		} else if err := writeFareHistorySummaries(os.Stdout, outputFormat, summaries); err != nil {
+			return reportError(err)
+		}
+		return ExitSuccess
+	}
+	if historyLimit > 0 && len(observations) > historyLimit {
+		observations = observations[len(observations)-historyLimit:]
+	}
+	if outputFormat == OutputTable {
+		renderFareHistoryTable(observations)
+	} else if err := writeFareObservations(os.Stdout, outputFormat, observations); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
+}
+
+// 收到中断信号时取消的 context
+func interruptContext(message string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case <-signals:
+			logger.Infof("[Flight-Go]%s", message)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// 价格监控（参数为监控配置文件, 收到中断信号时保存状态后退出）
+func executeWatchFunc(args []string) int {
+	if outputFormat == OutputJSON || outputFormat == OutputCSV {
+		return reportError(flightgo.NewInvalidArgumentError("watch 命令只支持 table 和 ndjson 输出格式"))
+	}
+	watchConfig, err := loadWatchConfig(args[0])
+	if err != nil {
+		return reportError(err)
+	}
+	statePath := watchStatePath
+	if statePath == "" {
+		statePath = watchConfig.StatePath
+	}
+	if statePath == "" {
+		statePath = defaultWatchStatePath()
+	}
+	ctx, cancel := interruptContext("收到退出信号, 正在保存监控状态")
+	defer cancel()
+	logger.Infof("[Flight-Go]开始监控 %d 条航线, 状态文件: %s", len(watchConfig.Routes), statePath)
+	return reportError(runWatch(ctx, newFlightClient(watchProvider), watchConfig, statePath, watchOnce))
+}
+
+// HTTP 接口服务（参数为监听地址, 收到中断信号时等待进行中的请求完成后退出）
+func executeServeFunc(args []string) int {
+	server := &http.Server{
+		Addr:    args[0],
+		Handler: NewAPIServer(newFlightClient(serveProvider), serveTimeout),
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-signals
+		logger.Infof("[Flight-Go]收到退出信号, 正在关闭 HTTP 服务")
+		ctx, cancel := context.WithTimeout(context.Background(), serveTimeout)
+		defer cancel()
+		shutdownErr <- server.Shutdown(ctx)
+	}()
+	logger.Infof("[Flight-Go]HTTP 服务已启动, 监听地址: %s, 接口描述: %s", args[0], apiOpenAPIPath)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return reportError(err)
+	}
+	return reportError(<-shutdownErr)
+}
+
+// 城市数据（list: 列出城市; update: 从网络更新本地缓存）
+func executeCitiesFunc(args []string) int {
+	switch args[0] {
+	case "list":
+		cities := newFlightClient("").Cities()
+		if outputFormat == OutputTable {
+			renderCityTable(cities)
+		} else if err := writeCities(os.Stdout, outputFormat, cities); err != nil {
+			return reportError(err)
+		}
+	case "update":
+		cachePath, count, err := newFlightClient("").UpdateCityCache(context.Background())
+		if err != nil {
+			return reportError(err)
+		}
+		logger.Infof("[Flight-Go]更新城市数据成功, 共 %d 个城市, 缓存文件: %s", count, cachePath)
+	default:
+		return reportError(flightgo.NewInvalidArgumentError("未知的 cities 子命令: %s", args[0]))
+	}
+	return ExitSuccess
+}
+
+// 航班状态码（list: 列出状态码映射和日志中的未知状态码; unknown: 列出未知状态码日志）
+func executeStatusCodesFunc(args []string) int {
+	unknowns, err := readUnknownFlightStatuses(defaultStatusLogPath())
+	if err != nil {
+		return reportError(err)
+	}
+	switch args[0] {
+	case "list":
+		entries := flightStatusCodeEntries(config.statusCodes, unknowns)
+		if outputFormat == OutputTable {
+			renderFlightStatusCodeTable(entries)
+		} else if err := writeFlightStatusCodeEntries(os.Stdout, outputFormat, entries); err != nil {
+			return reportError(err)
+		}
+	case "unknown":
+		if outputFormat == OutputTable {
+			renderUnknownFlightStatusTable(unknowns)
+		} else if err := writeUnknownFlightStatuses(os.Stdout, outputFormat, unknowns); err != nil {
+			return reportError(err)
+		}
+	default:
+		return reportError(flightgo.NewInvalidArgumentError("未知的 status-codes 子命令: %s", args[0]))
+	}
+	return ExitSuccess
+}
+
+// 查询机场信息（第一个参数为 stats 时统计准点率）
 func executeAirportInfoTableFunc(args []string) int {
-	airportInfoTable := NewVariFlightCrawler()
-	airportInfoTable.runAirportInfo(args[0], args[1])
-	return 1
+	if len(args) > 0 && args[0] == "stats" {
+		return executeAirportStatsFunc(args[1:])
+	}
+	if err := checkArgCount("airport", args, 2); err != nil {
+		return reportError(err)
+	}
+	newRequest := func(now time.Time) (flightgo.AirportBoardRequest, error) {
+		return airportBoardRequestFromFlags(args[0], args[1], now)
+	}
+	client := newFlightClient(airportInfoProvider)
+	if airportFollow {
+		if outputFormat != OutputTable {
+			return reportError(flightgo.NewInvalidArgumentError("-follow 只支持 table 输出格式"))
+		}
+		ctx, cancel := interruptContext("收到退出信号, 停止刷新进出港航班")
+		defer cancel()
+		return reportError(runAirportFollow(ctx, client, newRequest, boardFilterFromFlags(), airportInterval))
+	}
+	req, err := newRequest(time.Now())
+	if err != nil {
+		return reportError(err)
+	}
+	entries, err := client.AirportBoard(context.Background(), req)
+	if err != nil {
+		return reportError(err)
+	}
+	recordBoardHistory(entries)
+	entries = boardFilterFromFlags().Apply(entries)
+	if outputFormat == OutputTable {
+		renderAirportInfoTable(args[1], entries, nil)
+	} else if err := writeBoardEntries(os.Stdout, outputFormat, entries); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
+}
+
+// 统计机场进出港航班的准点率（参数: <城市名|机场名|机场代码> [arr|dep]; 汇总本地记录的历次查询和本次查询的航班）
+func executeAirportStatsFunc(args []string) int {
+	if err := checkArgCount("airport stats", args, 1); err != nil {
+		return reportError(err)
+	}
+	directions := []string{flightgo.DirectionDeparture, flightgo.DirectionArrival}
+	if len(args) > 1 {
+		if args[1] != flightgo.DirectionDeparture && args[1] != flightgo.DirectionArrival {
+			return reportError(flightgo.NewInvalidArgumentError("进出港类别错误: %s（可选: dep, arr）", args[1]))
+		}
+		directions = args[1:2]
+	}
+	if airportStatsTop < 0 {
+		return reportError(flightgo.NewInvalidArgumentError("top 不能为负数"))
+	}
+	since, err := parseHistoryDate("-since", airportStatsSince)
+	if err != nil {
+		return reportError(err)
+	}
+	until, err := parseHistoryDate("-until", airportStatsUntil)
+	if err != nil {
+		return reportError(err)
+	}
+	if !until.IsZero() {
+		// 包含结束日期当天
+		until = until.AddDate(0, 0, 1)
+	}
+	client := newFlightClient(airportInfoProvider)
+	airports, err := client.LookupAirports(args[0])
+	if err != nil {
+		return reportError(err)
+	}
+	filter := BoardHistoryFilter{Airports: airports, Since: since, Until: until}
+	if len(directions) == 1 {
+		filter.Direction = directions[0]
+	}
+	observations, err := NewHistoryStore(defaultHistoryDir()).QueryBoard(filter)
+	if err != nil {
+		return reportError(err)
+	}
+	if !airportStatsOffline {
+		now := time.Now()
+		for _, direction := range directions {
+			req, err := airportBoardRequestFromFlags(args[0], direction, now)
+			if err != nil {
+				return reportError(err)
+			}
+			entries, err := client.AirportBoard(context.Background(), req)
+			if err != nil {
+				// 有本地记录时查询失败只统计本地记录
+				if len(observations) == 0 {
+					return reportError(err)
+				}
+				logger.Warnf("[Flight-Go]查询机场 %s 进出港航班失败, 只统计本地记录, 错误原因: %v", args[0], err)
+				continue
+			}
+			recordBoardHistory(entries)
+			for _, observation := range boardObservations(entries, now) {
+				if filter.match(observation) {
+					observations = append(observations, observation)
+				}
+			}
+		}
+	}
+	entries, polls := latestBoardEntries(observations)
+	report := summarizeBoardStats(boardFilterFromFlags().Apply(entries), polls, airportStatsTop)
+	if outputFormat == OutputTable {
+		renderBoardStatsTable(report)
+	} else if err := writeBoardStats(os.Stdout, outputFormat, report.All()); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
 }
 
 // 查询航班号信息
 func executeFlightNumberInfoTableFunc(args []string) int {
-	flightNumberTable := NewVariFlightCrawler()
-	flightNumberTable.runFlightInfo(args[0], args[1])
-	return 1
+	if err := checkArgCount("code", args, 2); err != nil {
+		return reportError(err)
+	}
+	if flightNumberTrack {
+		return executeTrackFunc(args)
+	}
+	statuses, err := newFlightClient(flightNumberProvider).FlightStatus(context.Background(), args[0], args[1])
+	if err != nil {
+		return reportError(err)
+	}
+	if outputFormat == OutputTable {
+		renderFlightInfoTable(statuses)
+	} else if err := writeFlightStatuses(os.Stdout, outputFormat, statuses); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
+}
+
+// 持续跟踪航班动态（到达、取消或备降/返航落地后退出）
+func executeTrackFunc(args []string) int {
+	if outputFormat == OutputJSON || outputFormat == OutputCSV {
+		return reportError(flightgo.NewInvalidArgumentError("-track 只支持 table 和 ndjson 输出格式"))
+	}
+	ctx, cancel := interruptContext("收到退出信号, 停止跟踪航班动态")
+	defer cancel()
+	logger.Infof("[Flight-Go]开始跟踪航班 %s（%s）, 查询间隔: %s", args[0], args[1], flightNumberInterval)
+	return reportError(runTrack(ctx, newFlightClient(flightNumberProvider), args[0], args[1], flightNumberInterval))
 }
 
 // 查询国际航班信息
 func executeOverSeaFlightTableFunc(args []string) int {
-	flightTable := NewCtripCrawler()
+	if err := checkArgCount("oversea", args, 3); err != nil {
+		return reportError(err)
+	}
 	if len(args) < 4 {
 		args = append(args, "")
 	}
-	flightTable.runOverSeaFlightTableCrawler(args[0], args[1], args[2], args[3])
-	return 1
+	filter, err := itineraryFilterFromFlags()
+	if err != nil {
+		return reportError(err)
+	}
+	if filter.RequireMeal || filter.MinPunctuality > 0 {
+		return reportError(flightgo.NewInvalidArgumentError("国际航班数据中没有餐食和准点率, 不支持 -meal 和 -min-punctuality"))
+	}
+	order, err := itinerarySortFromFlags()
+	if err != nil {
+		return reportError(err)
+	}
+	req := flightgo.InternationalSearchRequest{
+		Departure:    args[0],
+		Arrival:      args[1],
+		Date:         args[2],
+		Cabin:        args[3],
+		PollInterval: flightOverSeaInterval,
+		Timeout:      flightOverSeaTimeout,
+	}
+	// 没有指定排序时边加载边输出（table 和 ndjson）, 每个行程确定后输出一次
+	streaming := order.Key == "" && (outputFormat == OutputTable || outputFormat == OutputNDJSON)
+	if streaming {
+		req.OnItineraries = func(page []flightgo.Itinerary) {
+			page = filter.Apply(page)
+			if outputFormat == OutputTable {
+				renderOverSeaFlightTable(page, args[3])
+			} else if err := writeItineraries(os.Stdout, outputFormat, page); err != nil {
+				logger.Errorf("[Flight-Go]输出国际航班失败, 错误原因: %v", err)
+			}
+		}
+	}
+	itineraries, err := newFlightClient(flightOverSeaProvider).SearchInternational(context.Background(), req)
+	if err != nil {
+		return reportError(err)
+	}
+	recordFareHistory(MarketInternational, args[0], args[1], args[2], itineraries)
+	if streaming {
+		return ExitSuccess
+	}
+	itineraries = order.Apply(filter.Apply(itineraries))
+	if outputFormat == OutputTable {
+		renderOverSeaFlightTable(itineraries, args[3])
+	} else if err := writeItineraries(os.Stdout, outputFormat, itineraries); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
 }
 
 // 查询国内航班信息
 func executeFlightTableFunc(args []string) int {
-	flightTable := NewCtripCrawler()
-	flightTable.runMainLandFlightTableCrawler(args[0], args[1], args[2], "Oneway", true)
-	return 1
+	if err := checkArgCount("schedule", args, 3); err != nil {
+		return reportError(err)
+	}
+	filter, err := itineraryFilterFromFlags()
+	if err != nil {
+		return reportError(err)
+	}
+	order, err := itinerarySortFromFlags()
+	if err != nil {
+		return reportError(err)
+	}
+	if flightTableMultiCity {
+		return executeMultiCityFunc(args, filter, order)
+	}
+	if flightTableFlexDays > 0 || flightTableUntilDate != "" {
+		return executeFareCalendarFunc(args, filter)
+	}
+	if len(args) > 3 {
+		return executeRoundTripFunc(args, filter, order)
+	}
+	itineraries, err := newFlightClient(flightTableProvider).SearchDomestic(context.Background(), flightgo.DomesticSearchRequest{
+		Departure: args[0],
+		Arrival:   args[1],
+		Date:      args[2],
+		TripType:  flightgo.TripTypeOneway,
+	})
+	if err != nil {
+		return reportError(err)
+	}
+	recordFareHistory(MarketDomestic, args[0], args[1], args[2], itineraries)
+	itineraries = order.Apply(filter.Apply(itineraries))
+	if outputFormat == OutputTable {
+		renderMainLandFlightTable(itineraries, !flightTableAllFares)
+	} else if err := writeItineraries(os.Stdout, outputFormat, itineraries); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
+}
+
+// 查询国内往返航班（第 4 个参数为返程日期）
+func executeRoundTripFunc(args []string, filter flightgo.ItineraryFilter, order flightgo.ItinerarySort) int {
+	trip, err := newFlightClient(flightTableProvider).SearchRoundTrip(context.Background(), flightgo.DomesticSearchRequest{
+		Departure:  args[0],
+		Arrival:    args[1],
+		Date:       args[2],
+		ReturnDate: args[3],
+	})
+	if err != nil {
+		return reportError(err)
+	}
+	recordTripHistory(trip)
+	trip = order.ApplyTrip(filter.ApplyTrip(trip))
+	if outputFormat == OutputTable {
+		renderTripTable(trip, !flightTableAllFares)
+	} else if err := writeTrip(os.Stdout, outputFormat, trip); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
+}
+
+// 查询国内多城市行程（参数按 <起飞机场> <到达机场> <日期> 三个一组, 每组为一段）
+func executeMultiCityFunc(args []string, filter flightgo.ItineraryFilter, order flightgo.ItinerarySort) int {
+	if len(args)%3 != 0 {
+		return reportError(flightgo.NewInvalidArgumentError("多城市行程的参数需要按 <起飞机场> <到达机场> <日期> 三个一组, 实际 %d 个", len(args)))
+	}
+	segments := make([]flightgo.SegmentRequest, 0, len(args)/3)
+	for i := 0; i < len(args); i += 3 {
+		segments = append(segments, flightgo.SegmentRequest{Departure: args[i], Arrival: args[i+1], Date: args[i+2]})
+	}
+	trip, err := newFlightClient(flightTableProvider).SearchMultiCity(context.Background(), segments)
+	if err != nil {
+		return reportError(err)
+	}
+	recordTripHistory(trip)
+	trip = order.ApplyTrip(filter.ApplyTrip(trip))
+	if outputFormat == OutputTable {
+		renderTripTable(trip, !flightTableAllFares)
+	} else if err := writeTrip(os.Stdout, outputFormat, trip); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
+}
+
+// 查询价格日历（-flex: 前后 N 天; -until: 从查询日期到指定日期）
+func executeFareCalendarFunc(args []string, filter flightgo.ItineraryFilter) int {
+	startDate, endDate := args[2], flightTableUntilDate
+	if flightTableFlexDays > 0 {
+		if flightTableUntilDate != "" {
+			return reportError(flightgo.NewInvalidArgumentError("-flex 和 -until 不能同时使用"))
+		}
+		var err error
+		if startDate, endDate, err = flightgo.FlexDateRange(args[2], flightTableFlexDays); err != nil {
+			return reportError(err)
+		}
+	}
+	days, err := newFlightClient(flightTableProvider).FareCalendar(context.Background(), flightgo.FareCalendarRequest{
+		Departure: args[0],
+		Arrival:   args[1],
+		StartDate: startDate,
+		EndDate:   endDate,
+		Filter:    filter,
+	})
+	if err != nil {
+		return reportError(err)
+	}
+	if outputFormat == OutputTable {
+		renderFareCalendarTable(days)
+	} else if err := writeFareCalendar(os.Stdout, outputFormat, days); err != nil {
+		return reportError(err)
+	}
+	return ExitSuccess
 }
 
 // 命令行初始化
@@ -88,6 +676,11 @@ func commandLineInit() {
 	flightTableCommand.Flag.StringVar(&flightDepartureCityName, "dep", "", "需要查询的始发地")
 	flightTableCommand.Flag.StringVar(&flightArrivalCityName, "arr", "", "需要查询的目的地")
 	flightTableCommand.Flag.StringVar(&flightDate, "date", "", "需要搜索的日期（格式: YYYY-MM-DD 例如: 2019-10-17）")
+	flightTableCommand.Flag.StringVar(&flightTableProvider, "provider", "", "数据源（默认: ctrip）")
+	flightTableCommand.Flag.IntVar(&flightTableFlexDays, "flex", 0, "价格日历: 查询日期前后 N 天每天的最低价格")
+	flightTableCommand.Flag.StringVar(&flightTableUntilDate, "until", "", "价格日历: 查询从查询日期到该日期每天的最低价格（格式: YYYY-MM-DD）")
+	flightTableCommand.Flag.BoolVar(&flightTableAllFares, "all-fares", false, "列出每个舱位等级的全部票价（默认只显示最低价）")
+	flightTableCommand.Flag.BoolVar(&flightTableMultiCity, "multi", false, "多城市行程（参数按 <起飞机场> <到达机场> <日期> 三个一组, 每组为一段）")
 
 	// 国际航班信息
 	flightOverSeaTableCommand.Run = executeOverSeaFlightTableFunc
@@ -95,24 +688,121 @@ func commandLineInit() {
 	flightOverSeaTableCommand.Flag.StringVar(&flightOverSeaArrivalCityName, "arr", "", "需要查询的目的地")
 	flightOverSeaTableCommand.Flag.StringVar(&flightOverSeaDate, "date", "", "需要搜索的日期（格式: YYYY-MM-DD 例如: 2019-10-17）")
 	flightOverSeaTableCommand.Flag.StringVar(&flightOverSeaCabinType, "cabin", "", "舱位等级（经济舱，超级经济舱，商务/头等舱，商务舱，公务舱，头等舱）")
+	flightOverSeaTableCommand.Flag.StringVar(&flightOverSeaProvider, "provider", "", "数据源（默认: ctrip）")
+	flightOverSeaTableCommand.Flag.DurationVar(&flightOverSeaInterval, "interval", flightgo.DefaultInternationalPollInterval, "轮询查询结果的间隔（例如: 2s）")
+	flightOverSeaTableCommand.Flag.DurationVar(&flightOverSeaTimeout, "timeout", flightgo.DefaultInternationalSearchTimeout, "查询的最长时间, 超过后输出已加载的结果（例如: 2m）")
 
 	// 航班号信息
 	flightNumberInfoCommand.Run = executeFlightNumberInfoTableFunc
 	flightNumberInfoCommand.Flag.StringVar(&flightNumber, "flightNumber", "", "需要查询的航班号")
 	flightNumberInfoCommand.Flag.StringVar(&flightNumberCheckDate, "date", "", "需要搜索的日期（格式: YYYYMMDD 例如: 20191017）")
+	flightNumberInfoCommand.Flag.StringVar(&flightNumberProvider, "provider", "", "数据源（默认: variflight）")
+	flightNumberInfoCommand.Flag.BoolVar(&flightNumberTrack, "track", false, "持续跟踪航班动态, 展示状态变化, 到达、取消或备降/返航落地后退出")
+	flightNumberInfoCommand.Flag.DurationVar(&flightNumberInterval, "interval", defaultTrackInterval, "跟踪航班动态时的查询间隔（例如: 1m）")
 
 	// 机场信息
 	airportInfoCommand.Run = executeAirportInfoTableFunc
 	airportInfoCommand.Flag.StringVar(&airportName, "airportName", "", "需要查询机场名称（例如: 广州）")
 	airportInfoCommand.Flag.StringVar(&airportDepOrArr, "depOrArr", "", "进场的进出港类别")
+	airportInfoCommand.Flag.StringVar(&airportInfoProvider, "provider", "", "数据源（默认: variflight）")
+	airportInfoCommand.Flag.StringVar(&airportDate, "date", "", "只保留计划时间在该日期的航班（格式: YYYY-MM-DD; 默认不限制）")
+	airportInfoCommand.Flag.StringVar(&airportWindow, "window", "", "只显示该时间段的航班（格式: HH:MM-HH:MM, 例如: 08:00-12:00）")
+	airportInfoCommand.Flag.DurationVar(&airportNext, "next", 0, "只显示从现在开始该时长内的航班（例如: 3h）")
+	airportInfoCommand.Flag.StringVar(&boardAirlines, "airline", "", "航空公司代码（航班号前缀）, 多个以逗号分隔（例如: CZ,MU）")
+	airportInfoCommand.Flag.StringVar(&boardCity, "city", "", "出港航班的到达地或进港航班的出发地（城市名或机场名）")
+	airportInfoCommand.Flag.StringVar(&boardStatuses, "status", "", "航班状态, 多个以逗号分隔（例如: delayed,cancelled 或 延误,取消）")
+	airportInfoCommand.Flag.StringVar(&boardAircraft, "aircraft", "", "机型（例如: 320）")
+	airportInfoCommand.Flag.BoolVar(&airportFollow, "follow", false, "持续刷新进出港航班, 高亮状态或实际时间发生变化的航班")
+	airportInfoCommand.Flag.DurationVar(&airportInterval, "interval", defaultBoardFollowInterval, "持续刷新时的刷新间隔（例如: 30s）")
+	airportInfoCommand.Flag.StringVar(&airportStatsSince, "since", "", "stats: 只统计计划日期不早于该日期的航班（格式: YYYY-MM-DD）")
+	airportInfoCommand.Flag.StringVar(&airportStatsUntil, "until", "", "stats: 只统计计划日期不晚于该日期的航班（格式: YYYY-MM-DD）")
+	airportInfoCommand.Flag.BoolVar(&airportStatsOffline, "offline", false, "stats: 只统计本地记录的航班, 不查询当前的进出港航班")
+	airportInfoCommand.Flag.IntVar(&airportStatsTop, "top", defaultBoardStatsTopRoutes, "stats: 展示准点率最低的航线数量（0 为全部）")
+
+	// 航班过滤条件
+	for _, cmd := range []*FlightCommand{flightTableCommand, flightOverSeaTableCommand} {
+		cmd.Flag.StringVar(&filterAirlines, "airline", "", "航空公司名称或航班号前缀, 多个以逗号分隔（例如: MU,东方航空）")
+		cmd.Flag.StringVar(&filterDepartWindow, "depart", "", "起飞时间段（格式: HH:MM-HH:MM, 例如: 06:00-12:00）")
+		cmd.Flag.StringVar(&filterArriveWindow, "arrive", "", "到达时间段（格式: HH:MM-HH:MM）")
+		cmd.Flag.StringVar(&filterAircraft, "aircraft", "", "机型名称或代码（例如: 320）")
+		cmd.Flag.BoolVar(&filterMeal, "meal", false, "只显示提供餐食的航班（仅国内航班）")
+		cmd.Flag.Float64Var(&filterMinPunctuality, "min-punctuality", 0, "最低准点率（百分比, 例如: 80; 仅国内航班）")
+		cmd.Flag.Int64Var(&filterMaxPrice, "max-price", 0, "最高价格（元, 含税）")
+		cmd.Flag.IntVar(&filterMaxStops, "max-stops", -1, "最多中转次数（0 为只显示直飞）")
+		cmd.Flag.DurationVar(&filterMaxLayover, "max-layover", 0, "最长转机等待时间（例如: 3h）")
+		cmd.Flag.StringVar(&resultSortOrder, "sort", "", "排序方式: 字段[:asc|desc]（字段: price, economy, business, first, departure, arrival, duration, punctuality, stops）")
+	}
+
+	// 城市数据
+	citiesCommand.Run = executeCitiesFunc
+
+	// 航班状态码
+	statusCodesCommand.Run = executeStatusCodesFunc
+
+	// 价格历史
+	historyCommand.Run = executeHistoryFunc
+	historyCommand.Flag.StringVar(&historyFlightNumber, "flight", "", "只查询该航班号")
+	historyCommand.Flag.StringVar(&historyCabinName, "cabin", "", "只查询该舱位等级（例如: 经济舱）")
+	historyCommand.Flag.StringVar(&historySince, "since", "", "观测日期不早于（格式: YYYY-MM-DD）")
+	historyCommand.Flag.StringVar(&historyUntil, "until", "", "观测日期不晚于（格式: YYYY-MM-DD）")
+	historyCommand.Flag.IntVar(&historyLimit, "limit", 0, "只显示最近的 N 条记录")
+	historyCommand.Flag.BoolVar(&historySummary, "summary", false, "按出发日期和舱位等级汇总每次查询的最低价格")
+
+	// HTTP 接口服务
+	serveCommand.Run = executeServeFunc
+	serveCommand.Flag.DurationVar(&serveTimeout, "timeout", defaultServeTimeout, "单次查询的超时时间（例如: 30s）")
+	serveCommand.Flag.StringVar(&serveProvider, "provider", "", "数据源（默认: 按查询类型选择）")
+
+	// 价格监控
+	watchCommand.Run = executeWatchFunc
+	watchCommand.Flag.BoolVar(&watchOnce, "once", false, "只查询一轮（适合配合定时任务使用）")
+	watchCommand.Flag.StringVar(&watchStatePath, "state", "", "监控状态文件路径（默认: 监控配置中的 statePath 或用户缓存目录下的 flight-go/watch_state.json）")
+	watchCommand.Flag.StringVar(&watchProvider, "provider", "", "数据源（默认: ctrip）")
+
+	// 输出格式
+	for _, cmd := range flightCommands {
+		cmd.Flag.StringVar(&outputFormat, "output", OutputTable, "输出格式（table, json, ndjson, csv）")
+		cmd.Flag.StringVar(&configPath, "config", "", "配置文件路径（默认: 用户配置目录下的 flight-go/config.json）")
+		cmd.Flag.StringVar(&cassetteRecordDir, "record", "", "录制所有 HTTP 请求和响应到指定目录")
+		cmd.Flag.StringVar(&cassetteReplayDir, "replay", "", "从指定目录回放录制的 HTTP 响应（不访问网络）")
+		cmd.Flag.BoolVar(&historyDisabled, "no-history", false, "不记录本次查询的价格历史和进出港航班")
+	}
 }
 
 // 输出命令的使用方式
 func commandUsage() {
 	flag.Usage()
 	fmt.Println("\n参数(Options):")
-	fmt.Println("    schedule <起飞机场> <到达机场> <当前日期(日期格式: YYYY-MM-DD)>")
+	fmt.Println("    schedule <起飞机场> <到达机场> <当前日期(日期格式: YYYY-MM-DD)> [返程日期(指定时查询往返航班)]")
+	fmt.Println("    schedule -all-fares <起飞机场> <到达机场> <日期> (列出每个舱位等级的全部票价, 包括折扣和剩余座位)")
+	fmt.Println("    schedule -flex <天数> | -until <结束日期> <起飞机场> <到达机场> <日期> (价格日历, 每天各舱位等级的最低价格)")
+	fmt.Println("    schedule -multi <起飞机场> <到达机场> <日期> <起飞机场> <到达机场> <日期> ... (多城市行程, 每三个参数为一段)")
+	fmt.Println("    schedule/oversea 过滤条件: -airline <航空公司> -depart <HH:MM-HH:MM> -arrive <HH:MM-HH:MM> -aircraft <机型> -meal")
+	fmt.Println("        -min-punctuality <准点率> -max-price <价格> -max-stops <中转次数> -max-layover <转机时间>")
+	fmt.Println("    schedule/oversea 排序: -sort <字段>[:asc|desc] (price, economy, business, first, departure, arrival, duration, punctuality, stops)")
 	fmt.Println("    oversea <起飞地> <到达地> <当前日期(日期格式: YYYY-MM-DD)> <舱位等级>")
+	fmt.Println("    oversea -interval <轮询间隔> -timeout <最长时间> <起飞地> <到达地> <日期> <舱位等级> (边加载边输出, 超时后输出已加载的结果)")
 	fmt.Println("    code <航班号> <当前日期(日期格式: YYYYMMDD)>")
-	fmt.Println("    airport <城市名> <进出港字段(例如,进港: arr; 出港: dep)>")
+	fmt.Println("    code -track [-interval <查询间隔>] <航班号> <日期(日期格式: YYYYMMDD)> (持续跟踪航班动态, 到达、取消或备降/返航落地后退出)")
+	fmt.Println("    airport <城市名|机场名|机场代码> <进出港字段(例如,进港: arr; 出港: dep)> (城市名查询该城市全部机场, 例如: 北京、北京大兴、PKX、ZBAD)")
+	fmt.Println("    airport -date <YYYY-MM-DD> -window <HH:MM-HH:MM> | -next <时长> <城市名> <arr|dep> (自动翻页获取全天航班, 按日期和时间段过滤)")
+	fmt.Println("    airport 过滤条件: -airline <航空公司代码> -city <城市或机场> -status <状态> -aircraft <机型>")
+	fmt.Println("    airport -follow [-interval <刷新间隔>] <城市名> <arr|dep> (持续刷新进出港航班, 高亮发生变化的航班)")
+	fmt.Println("    airport [-since <YYYY-MM-DD>] [-until <YYYY-MM-DD>] [-offline] [-top <航线数>] stats <城市名|机场名|机场代码> [arr|dep]")
+	fmt.Println("        (统计本地记录的历次查询和本次查询的航班: 按机场和航空公司的准点率、平均和 90% 分位延误、取消数, 以及准点率最低的航线)")
+	fmt.Println("    watch [-once] [-state <状态文件>] <监控配置文件> (持续监控航线价格, 低于阈值或降幅超过设定百分比时提醒)")
+	fmt.Println("    history [-summary] <出发地> <到达地> [出发日期] (查询本地记录的价格历史)")
+	fmt.Println("    serve [-timeout <超时时间>] <监听地址(例如: :8080)> (启动 HTTP 接口服务, 接口描述见 /openapi.json)")
+	fmt.Println("    cities <list|update> (list: 列出城市和机场代码; update: 从网络更新城市数据缓存)")
+	fmt.Println("    status-codes <list|unknown> (list: 列出航班状态码映射; unknown: 列出本地记录的未知状态码)")
+	fmt.Println("\n通用参数(Flags):")
+	fmt.Println("    -provider <数据源名称> (需写在命令之后、查询参数之前, 例如: schedule -provider ctrip 北京 上海 2019-11-15)")
+	fmt.Println("    -output <输出格式> (table, json, ndjson, csv; 默认: table)")
+	fmt.Println("    -config <配置文件路径> (也可通过 FLIGHT_GO_CONFIG 环境变量指定)")
+	fmt.Println("    -record <目录> (录制所有 HTTP 请求和响应)")
+	fmt.Println("    -replay <目录> (回放录制的 HTTP 响应, 不访问网络)")
+	fmt.Println("    -no-history (不记录本次查询的价格历史和进出港航班; 回放时不会记录)")
+	fmt.Println("\n退出码(Exit codes):")
+	fmt.Println("    0 成功; 1 其他错误; 2 参数错误; 3 网络请求失败; 4 接口数据为空;")
+	fmt.Println("    5 接口数据解析失败; 6 未知的城市或机场; 7 被反爬虫拦截")
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+const (
+	configFileName string = "config.json"
+	configPathEnv  string = "FLIGHT_GO_CONFIG"
+)
+
+// 配置文件
+type Config struct {
+	Endpoints flightgo.Endpoints `json:"endpoints"`
+	// 价格历史目录（默认: 用户配置目录下的 flight-go/history）
+	HistoryDir string `json:"historyDir,omitempty"`
+	// 补充的航班状态码映射（例如: {"3": {"name": "备降", "phase": "diverted"}}）
+	FlightStatusCodes map[string]flightgo.FlightStatusCode `json:"flightStatusCodes,omitempty"`
+	// 未知状态码日志路径（默认: 用户配置目录下的 flight-go/unknown_status_codes.ndjson）
+	StatusLogPath string `json:"statusLogPath,omitempty"`
+
+	// 解析后的状态码映射
+	statusCodes map[int64]flightgo.FlightStatusCode
+}
+
+// 环境变量和配置项的对应关系
+func endpointEnvBindings(e *flightgo.Endpoints) map[string]*string {
+	return map[string]*string{
+		"FLIGHT_GO_CTRIP_BASE_URL":                 &e.CtripBaseURL,
+		"FLIGHT_GO_CTRIP_API_VERSION":              &e.CtripAPIVersion,
+		"FLIGHT_GO_VARIFLIGHT_BASE_URL":            &e.VariFlightBaseURL,
+		"FLIGHT_GO_CITY_NAME_CODE_URL":             &e.CityNameCodeURL,
+		"FLIGHT_GO_PLANE_API_URL":                  &e.PlaneAPIURL,
+		"FLIGHT_GO_API_REQUEST_ORIGIN":             &e.APIRequestOrigin,
+		"FLIGHT_GO_API_REQUEST_REFERER":            &e.APIRequestReferer,
+		"FLIGHT_GO_CITY_CODE_URL":                  &e.CityCodeURL,
+		"FLIGHT_GO_FORM_DATA_URL":                  &e.FormDataURL,
+		"FLIGHT_GO_OVERSEA_AIRPLANE_URL":           &e.OverSeaAirplaneURL,
+		"FLIGHT_GO_OVERSEA_AIRPLANE_PULL_DATA_URL": &e.OverSeaAirplanePullDataURL,
+		"FLIGHT_GO_AIRPORT_DEP_API_URL":            &e.AirportDepAPIURL,
+		"FLIGHT_GO_AIRPORT_ARR_API_URL":            &e.AirportArrAPIURL,
+		"FLIGHT_GO_FLIGHT_NUMBER_API_URL":          &e.FlightNumberAPIURL,
+	}
+}
+
+// 当前使用的配置
+var (
+	configPath string
+	config     = Config{Endpoints: flightgo.DefaultEndpoints()}
+)
+
+// 默认配置文件路径
+func defaultConfigPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "flight-go", configFileName)
+}
+
+// 加载配置（优先级: 环境变量 > 配置文件 > 默认值）
+// 配置文件路径依次取 -config 参数、FLIGHT_GO_CONFIG 环境变量和默认路径, 默认路径的文件不存在时忽略
+func loadConfig(path string) error {
+	loaded := Config{Endpoints: flightgo.DefaultEndpoints()}
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv(configPathEnv)
+		explicit = path != ""
+	}
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			if explicit || !os.IsNotExist(err) {
+				return fmt.Errorf("读取配置文件失败: %v", err)
+			}
+		} else if err := json.Unmarshal(data, &loaded); err != nil {
+			return fmt.Errorf("配置文件格式错误: %s, %v", path, err)
+		}
+	}
+	statusCodes, err := parseConfigFlightStatusCodes(loaded.FlightStatusCodes)
+	if err != nil {
+		return fmt.Errorf("配置文件格式错误: %s, %v", path, err)
+	}
+	loaded.statusCodes = statusCodes
+	for env, field := range endpointEnvBindings(&loaded.Endpoints) {
+		if value := os.Getenv(env); value != "" {
+			*field = value
+		}
+	}
+	config = loaded
+	return nil
+}
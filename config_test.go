@@ -0,0 +1,103 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// 隔离用户配置目录和环境变量, 测试结束后恢复当前配置
+func isolateConfig(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(configPathEnv, "")
+	for env := range endpointEnvBindings(&config.Endpoints) {
+		t.Setenv(env, "")
+	}
+	savedConfig := config
+	t.Cleanup(func() {
+		config = savedConfig
+	})
+}
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		// 期望的国内航班接口地址和航班号接口地址
+		planeAPIURL        string
+		flightNumberAPIURL string
+	}{
+		{
+			name:               "默认值",
+			planeAPIURL:        "https://flights.ctrip.com/itinerary/api/12808/products",
+			flightNumberAPIURL: "https://adsbapi.variflight.com/adsb/index/advancedSearch",
+		},
+		{
+			name:               "配置文件替换基础地址",
+			file:               `{"endpoints": {"ctripBaseURL": "http://127.0.0.1:8000", "ctripAPIVersion": "13000"}}`,
+			planeAPIURL:        "http://127.0.0.1:8000/itinerary/api/13000/products",
+			flightNumberAPIURL: "https://adsbapi.variflight.com/adsb/index/advancedSearch",
+		},
+		{
+			name: "环境变量优先于配置文件",
+			file: `{"endpoints": {"ctripBaseURL": "http://127.0.0.1:8000", "variFlightBaseURL": "http://127.0.0.1:9000"}}`,
+			env: map[string]string{
+				"FLIGHT_GO_CTRIP_BASE_URL":        "http://127.0.0.1:8001",
+				"FLIGHT_GO_FLIGHT_NUMBER_API_URL": "{variflight}/v2/search",
+			},
+			planeAPIURL:        "http://127.0.0.1:8001/itinerary/api/12808/products",
+			flightNumberAPIURL: "http://127.0.0.1:9000/v2/search",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateConfig(t)
+			for env, value := range tt.env {
+				t.Setenv(env, value)
+			}
+			path := ""
+			if tt.file != "" {
+				path = writeTestConfig(t, tt.file)
+			}
+			if err := loadConfig(path); err != nil {
+				t.Fatalf("加载配置失败: %v", err)
+			}
+			endpoints := config.Endpoints.Expand()
+			if endpoints.PlaneAPIURL != tt.planeAPIURL || endpoints.FlightNumberAPIURL != tt.flightNumberAPIURL {
+				t.Errorf("接口地址: %s %s, 期望 %s %s", endpoints.PlaneAPIURL, endpoints.FlightNumberAPIURL, tt.planeAPIURL, tt.flightNumberAPIURL)
+			}
+		})
+	}
+}
+
+func TestLoadConfigPath(t *testing.T) {
+	isolateConfig(t)
+	// FLIGHT_GO_CONFIG 指定的配置文件
+	t.Setenv(configPathEnv, writeTestConfig(t, `{"endpoints": {"variFlightBaseURL": "http://127.0.0.1:9000"}}`))
+	if err := loadConfig(""); err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if url := config.Endpoints.Expand().AirportDepAPIURL; url != "http://127.0.0.1:9000/adsb/airport/api/departures" {
+		t.Errorf("进出港接口地址: %s", url)
+	}
+	// 指定的配置文件不存在或格式错误时报错
+	if err := loadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("指定的配置文件不存在时应返回错误")
+	}
+	if err := loadConfig(writeTestConfig(t, `{"endpoints": `)); err == nil {
+		t.Errorf("配置文件格式错误时应返回错误")
+	}
+}
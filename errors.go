@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+// 退出码（0 为成功, 1 为其他未分类的错误）
+const (
+	ExitSuccess         = 0
+	ExitUnknownError    = 1
+	ExitInvalidArgument = 2
+	ExitNetwork         = 3
+	ExitUpstreamEmpty   = 4
+	ExitParse           = 5
+	ExitUnknownCity     = 6
+	ExitAntiBotBlocked  = 7
+)
+
+var errorKindExitCodes = map[flightgo.ErrorKind]int{
+	flightgo.ErrorNetwork:         ExitNetwork,
+	flightgo.ErrorUpstreamEmpty:   ExitUpstreamEmpty,
+	flightgo.ErrorParse:           ExitParse,
+	flightgo.ErrorInvalidArgument: ExitInvalidArgument,
+	flightgo.ErrorUnknownCity:     ExitUnknownCity,
+	flightgo.ErrorAntiBotBlocked:  ExitAntiBotBlocked,
+}
+
+// 错误对应的退出码
+func exitCodeOf(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+	var flightErr *flightgo.Error
+	if errors.As(err, &flightErr) {
+		if code, ok := errorKindExitCodes[flightErr.Kind]; ok {
+			return code
+		}
+	}
+	return ExitUnknownError
+}
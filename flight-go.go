@@ -3,6 +3,8 @@ package main
 import (
 	"os"
 	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
 )
 
 const (
@@ -26,21 +28,26 @@ func main() {
 			if cmd.Run != nil && cmd.Name() == args[1] {
 				err := cmd.Flag.Parse(args[2:])
 				if err != nil {
-					os.Exit(1)
+					os.Exit(ExitInvalidArgument)
+				}
+				if err := checkOutputFormat(outputFormat); err != nil {
+					os.Exit(reportError(flightgo.NewInvalidArgumentError("%v", err)))
+				}
+				if err := loadConfig(configPath); err != nil {
+					os.Exit(reportError(err))
+				}
+				if err := configureCassette(); err != nil {
+					os.Exit(reportError(err))
 				}
 				args = cmd.Flag.Args()
 				if len(args) > 0 {
-					// 初始化数据
-					initCityNameCodeData()
 					os.Exit(cmd.Run(args))
 				}
-				logger.Errorf("[Flight-Go]命令参数错误!")
 				break
 			}
 		}
-		commandUsage()
-	} else {
-		logger.Errorf("[Flight-Go]命令参数错误!")
-		commandUsage()
 	}
+	logger.Errorf("[Flight-Go]命令参数错误!")
+	commandUsage()
+	os.Exit(ExitInvalidArgument)
 }
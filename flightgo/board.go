@@ -0,0 +1,163 @@
+package flightgo
+
+import "strings"
+
+const (
+	// 机场进出港查询的日期格式
+	AirportBoardDateLayout string = "2006-01-02"
+	// 机场进出港默认每页数量（与飞常准网页一致）
+	DefaultAirportBoardPageSize int = 15
+	// 最多翻页次数（防止接口返回的总数异常时一直翻页）
+	airportBoardMaxPages int = 200
+)
+
+// 实际时间, 没有时为预计时间, 都没有时为计划时间
+func (e BoardEntry) ExpectedTime() DateTime {
+	switch {
+	case !e.ActualTime.IsZero():
+		return e.ActualTime
+	case !e.EstimatedTime.IsZero():
+		return e.EstimatedTime
+	}
+	return e.ScheduledTime
+}
+
+// 航班的唯一标识（用于翻页时去重和比较前后两次查询结果）
+func (e BoardEntry) Key() string {
+	return e.Direction + "|" + e.FlightNumber + "|" + e.Departure.Name + "|" + e.Arrival.Name + "|" + e.ScheduledTime.String()
+}
+
+// 时间是否在范围内（包含两端）
+func (req AirportBoardRequest) inRange(t DateTime) bool {
+	if t.IsZero() {
+		return false
+	}
+	return (req.Since.IsZero() || !t.Before(req.Since)) && (req.Until.IsZero() || !t.After(req.Until))
+}
+
+// 按日期（北京时间）和时间范围过滤
+func (req AirportBoardRequest) filterEntries(entries []BoardEntry) []BoardEntry {
+	if req.Date == "" && req.Since.IsZero() && req.Until.IsZero() {
+		return entries
+	}
+	filtered := make([]BoardEntry, 0, len(entries))
+	for _, entry := range entries {
+		if req.Date != "" && entry.ScheduledTime.In(ChinaLocation).Format(AirportBoardDateLayout) != req.Date {
+			continue
+		}
+		if (!req.Since.IsZero() || !req.Until.IsZero()) && !req.inRange(entry.ScheduledTime) && !req.inRange(entry.ExpectedTime()) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// 查询的机场（出港航班的出发地, 或进港航班的到达地）
+func (e BoardEntry) LocalEnd() Airport {
+	if e.Direction == DirectionArrival {
+		return e.Arrival
+	}
+	return e.Departure
+}
+
+// 补充查询的机场的代码（接口数据中只有机场名称）
+func (e *BoardEntry) setLocalAirport(airport Airport) {
+	local := &e.Departure
+	if e.Direction == DirectionArrival {
+		local = &e.Arrival
+	}
+	if local.CityName == "" {
+		local.CityName = airport.CityName
+	}
+	if local.Name == "" {
+		local.Name = airport.Name
+	}
+	local.IATA = airport.IATA
+	local.ICAO = airport.ICAO
+}
+
+// 出港航班的到达地, 或进港航班的出发地
+func (e BoardEntry) OtherEnd() Airport {
+	if e.Direction == DirectionArrival {
+		return e.Departure
+	}
+	return e.Arrival
+}
+
+// 进出港航班过滤条件（零值表示不限制）
+type BoardFilter struct {
+	// 航空公司代码（航班号前缀, 例如: CZ）, 满足其中一个即可
+	Airlines []string
+	// 航班号
+	FlightNumber string
+	// 出港航班的到达地或进港航班的出发地（城市名或机场名, 例如: 上海 或 虹桥）
+	City string
+	// 状态（阶段标识或中文名称, 例如: delayed、取消; 其他文字按状态名称包含匹配）, 满足其中一个即可
+	Statuses []string
+	// 机型（例如: 320）
+	Aircraft string
+}
+
+// 是否没有设置任何条件
+func (f BoardFilter) IsZero() bool {
+	return len(f.Airlines) == 0 && f.FlightNumber == "" && f.City == "" && len(f.Statuses) == 0 && f.Aircraft == ""
+}
+
+// 航班是否满足状态条件
+func (f BoardFilter) matchStatus(entry BoardEntry) bool {
+	for _, status := range f.Statuses {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			continue
+		}
+		if phase, err := ParseFlightPhase(status); err == nil && entry.Phase == phase {
+			return true
+		}
+		if strings.Contains(entry.Status, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// 航班是否满足航空公司条件
+func (f BoardFilter) matchAirline(entry BoardEntry) bool {
+	for _, airline := range f.Airlines {
+		airline = strings.TrimSpace(airline)
+		if airline != "" && strings.HasPrefix(strings.ToUpper(entry.FlightNumber), strings.ToUpper(airline)) {
+			return true
+		}
+	}
+	return false
+}
+
+// 航班是否满足条件
+func (f BoardFilter) Match(entry BoardEntry) bool {
+	switch {
+	case len(f.Airlines) > 0 && !f.matchAirline(entry),
+		f.FlightNumber != "" && !strings.EqualFold(entry.FlightNumber, f.FlightNumber),
+		len(f.Statuses) > 0 && !f.matchStatus(entry),
+		f.Aircraft != "" && !strings.Contains(strings.ToUpper(entry.AircraftType), strings.ToUpper(f.Aircraft)):
+		return false
+	}
+	if f.City != "" {
+		place := entry.OtherEnd()
+		return strings.Contains(place.CityName, f.City) || strings.Contains(place.Name, f.City)
+	}
+	return true
+}
+
+// 过滤进出港航班
+func (f BoardFilter) Apply(entries []BoardEntry) []BoardEntry {
+	if f.IsZero() {
+		return entries
+	}
+	filtered := make([]BoardEntry, 0, len(entries))
+	for _, entry := range entries {
+		if f.Match(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
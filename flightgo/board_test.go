@@ -0,0 +1,176 @@
+package flightgo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBoardTime(day, hour, min int) DateTime {
+	return DateTime{Time: time.Date(2019, 11, day, hour, min, 0, 0, ChinaLocation)}
+}
+
+func TestBoardEntryExpectedTime(t *testing.T) {
+	scheduled, estimated, actual := testBoardTime(15, 8, 0), testBoardTime(15, 8, 20), testBoardTime(15, 8, 25)
+	tests := []struct {
+		name  string
+		entry BoardEntry
+		want  DateTime
+	}{
+		{"实际时间", BoardEntry{ScheduledTime: scheduled, EstimatedTime: estimated, ActualTime: actual}, actual},
+		{"预计时间", BoardEntry{ScheduledTime: scheduled, EstimatedTime: estimated}, estimated},
+		{"计划时间", BoardEntry{ScheduledTime: scheduled}, scheduled},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.ExpectedTime(); !got.Equal(tt.want.Time) {
+			t.Errorf("%s: %s, 期望 %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAirportBoardRequestFilterEntries(t *testing.T) {
+	entries := []BoardEntry{
+		{FlightNumber: "CZ3100", ScheduledTime: testBoardTime(15, 6, 0)},
+		// 计划时间不在范围内, 但延误后的预计时间在范围内
+		{FlightNumber: "MU3107", ScheduledTime: testBoardTime(15, 7, 30), EstimatedTime: testBoardTime(15, 8, 10)},
+		{FlightNumber: "CA3114", ScheduledTime: testBoardTime(15, 9, 0)},
+		{FlightNumber: "CZ3999", ScheduledTime: testBoardTime(16, 0, 30)},
+	}
+	tests := []struct {
+		name          string
+		req           AirportBoardRequest
+		flightNumbers string
+	}{
+		{"不过滤", AirportBoardRequest{}, "CZ3100 MU3107 CA3114 CZ3999"},
+		{"日期", AirportBoardRequest{Date: "2019-11-15"}, "CZ3100 MU3107 CA3114"},
+		{"时间范围", AirportBoardRequest{Since: testBoardTime(15, 8, 0).Time, Until: testBoardTime(15, 12, 0).Time}, "MU3107 CA3114"},
+		{"只有开始时间", AirportBoardRequest{Since: testBoardTime(15, 8, 30).Time}, "CA3114 CZ3999"},
+		{"日期和时间范围", AirportBoardRequest{Date: "2019-11-15", Since: testBoardTime(15, 8, 30).Time}, "CA3114"},
+	}
+	for _, tt := range tests {
+		filtered := tt.req.filterEntries(entries)
+		flightNumbers := make([]string, 0, len(filtered))
+		for _, entry := range filtered {
+			flightNumbers = append(flightNumbers, entry.FlightNumber)
+		}
+		if got := strings.Join(flightNumbers, " "); got != tt.flightNumbers {
+			t.Errorf("%s: %s, 期望 %s", tt.name, got, tt.flightNumbers)
+		}
+	}
+}
+
+func TestClientAirportBoardPaging(t *testing.T) {
+	pages := map[string]string{
+		"1": readCassetteBody(t, "airport-dep-paged/ca5cb1bb9de38622-001.json"),
+		"2": readCassetteBody(t, "airport-dep-paged/d0587abb1030db4d-001.json"),
+	}
+	requested := make([]string, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/adsb/airport/api/departures", func(w http.ResponseWriter, r *http.Request) {
+		pageNum := r.URL.Query().Get("pageNum")
+		requested = append(requested, pageNum)
+		// 接口没有日期参数, 日期在本地过滤
+		if _, ok := r.URL.Query()["date"]; ok {
+			t.Errorf("请求中不应包含日期参数: %s", r.URL)
+		}
+		if r.URL.Query().Get("pageSize") != "15" {
+			t.Errorf("每页数量: %s", r.URL.Query().Get("pageSize"))
+		}
+		_, _ = w.Write([]byte(pages[pageNum]))
+	})
+	client := newTestClient(t, mux)
+	entries, err := client.AirportBoard(context.Background(), AirportBoardRequest{Airport: "广州", Direction: DirectionDeparture})
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	// 取完接口返回的总数后停止翻页, 第二页重复的航班只保留一次
+	if strings.Join(requested, ",") != "1,2" {
+		t.Errorf("请求的页码: %v", requested)
+	}
+	if len(entries) != 18 || entries[0].FlightNumber != "CZ3100" || entries[17].FlightNumber != "CZ3999" {
+		t.Errorf("航班数量: %d", len(entries))
+	}
+	// 指定日期时去掉第二天凌晨的航班
+	entries, err = client.AirportBoard(context.Background(), AirportBoardRequest{Airport: "广州", Direction: DirectionDeparture, Date: "2019-11-15"})
+	if err != nil || len(entries) != 17 || entries[16].FlightNumber == "CZ3999" {
+		t.Errorf("指定日期的航班: %d 条（%v）", len(entries), err)
+	}
+}
+
+func TestBoardFilterApply(t *testing.T) {
+	entries := []BoardEntry{
+		{Direction: DirectionDeparture, FlightNumber: "CZ3101", AircraftType: "A320", Status: "起飞", Phase: FlightPhaseDeparted,
+			Arrival: Airport{CityName: "北京", Name: "北京首都"}},
+		{Direction: DirectionDeparture, FlightNumber: "CZ3539", AircraftType: "B738", Status: "延误", Phase: FlightPhaseDelayed,
+			Arrival: Airport{CityName: "上海", Name: "上海虹桥"}},
+		{Direction: DirectionDeparture, FlightNumber: "HU7808", AircraftType: "A321", Status: "提前取消", Phase: FlightPhaseCancelled,
+			Arrival: Airport{CityName: "海口", Name: "海口美兰"}},
+		// 进港航班按出发地匹配城市
+		{Direction: DirectionArrival, FlightNumber: "MU5301", AircraftType: "A320", Status: "计划", Phase: FlightPhaseScheduled,
+			Departure: Airport{CityName: "上海", Name: "上海浦东"}, Arrival: Airport{CityName: "广州", Name: "广州白云"}},
+	}
+	tests := []struct {
+		name          string
+		filter        BoardFilter
+		flightNumbers string
+	}{
+		{"不过滤", BoardFilter{}, "CZ3101 CZ3539 HU7808 MU5301"},
+		{"航空公司", BoardFilter{Airlines: []string{"cz", " HU "}}, "CZ3101 CZ3539 HU7808"},
+		{"航班号", BoardFilter{FlightNumber: "mu5301"}, "MU5301"},
+		{"城市名", BoardFilter{City: "上海"}, "CZ3539 MU5301"},
+		{"机场名", BoardFilter{City: "虹桥"}, "CZ3539"},
+		{"阶段标识和中文名称", BoardFilter{Statuses: []string{"delayed", "取消"}}, "CZ3539 HU7808"},
+		{"状态名称包含匹配", BoardFilter{Statuses: []string{"提前"}}, "HU7808"},
+		{"机型", BoardFilter{Aircraft: "320"}, "CZ3101 MU5301"},
+		{"多个条件", BoardFilter{Airlines: []string{"CZ"}, Aircraft: "320"}, "CZ3101"},
+	}
+	for _, tt := range tests {
+		filtered := tt.filter.Apply(entries)
+		flightNumbers := make([]string, 0, len(filtered))
+		for _, entry := range filtered {
+			flightNumbers = append(flightNumbers, entry.FlightNumber)
+		}
+		if got := strings.Join(flightNumbers, " "); got != tt.flightNumbers {
+			t.Errorf("%s: %s, 期望 %s", tt.name, got, tt.flightNumbers)
+		}
+	}
+}
+
+func TestClientAirportBoardCity(t *testing.T) {
+	bodies := map[string]string{
+		"PEK": readCassetteBody(t, "airport-dep-city/cd1291abe0339015-001.json"),
+		"PKX": readCassetteBody(t, "airport-dep-city/0688f991c5b3d27d-001.json"),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/adsb/airport/api/departures", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := bodies[r.URL.Query().Get("iata")]
+		if !ok {
+			http.Error(w, "unknown airport", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	})
+	client := newTestClient(t, mux)
+	// 城市名合并全部机场的航班, 按计划时间排序
+	entries, err := client.AirportBoard(context.Background(), AirportBoardRequest{Airport: "北京", Direction: DirectionDeparture})
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	airports := make(map[string]int)
+	for i, entry := range entries {
+		if i > 0 && entry.ScheduledTime.Before(entries[i-1].ScheduledTime.Time) {
+			t.Errorf("第 %d 条没有按计划时间排序", i+1)
+		}
+		airports[entry.Departure.IATA]++
+	}
+	if len(entries) != 5 || airports["PEK"] != 3 || airports["PKX"] != 2 {
+		t.Errorf("航班: %d 条, 各机场: %v", len(entries), airports)
+	}
+	// 机场名只查询该机场
+	entries, err = client.AirportBoard(context.Background(), AirportBoardRequest{Airport: "北京大兴", Direction: DirectionDeparture})
+	if err != nil || len(entries) != 2 || entries[0].Departure.ICAO != "ZBAD" {
+		t.Errorf("大兴机场: %+v（%v）", entries, err)
+	}
+}
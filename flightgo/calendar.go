@@ -0,0 +1,145 @@
+package flightgo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// 日期格式
+	dateLayout string = "2006-01-02"
+	// 价格日历最多查询的天数
+	maxFareCalendarDays = 62
+	// 默认同时查询的天数
+	defaultFareCalendarConcurrency = 3
+)
+
+// 价格日历查询请求
+type FareCalendarRequest struct {
+	// 出发城市和到达城市（城市名, 例如: 北京）
+	Departure string
+	Arrival   string
+	// 开始和结束日期（格式: YYYY-MM-DD, 包含结束日期）
+	StartDate string
+	EndDate   string
+	// 同时查询的天数（默认: 3）
+	Concurrency int
+	// 汇总前过滤每天的航班
+	Filter ItineraryFilter
+}
+
+// 以某一天为中心的前后 N 天（返回开始和结束日期）
+func FlexDateRange(date string, days int) (string, string, error) {
+	center, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return "", "", NewInvalidArgumentError("日期格式错误: %s（格式: YYYY-MM-DD）", date)
+	}
+	if days < 0 {
+		return "", "", NewInvalidArgumentError("前后天数不能为负数: %d", days)
+	}
+	return center.AddDate(0, 0, -days).Format(dateLayout), center.AddDate(0, 0, days).Format(dateLayout), nil
+}
+
+// 开始和结束日期之间的每一天
+func calendarDates(startDate, endDate string) ([]string, error) {
+	start, err := time.Parse(dateLayout, startDate)
+	if err != nil {
+		return nil, NewInvalidArgumentError("开始日期格式错误: %s（格式: YYYY-MM-DD）", startDate)
+	}
+	end, err := time.Parse(dateLayout, endDate)
+	if err != nil {
+		return nil, NewInvalidArgumentError("结束日期格式错误: %s（格式: YYYY-MM-DD）", endDate)
+	}
+	if end.Before(start) {
+		return nil, NewInvalidArgumentError("结束日期 %s 早于开始日期 %s", endDate, startDate)
+	}
+	dates := make([]string, 0)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if len(dates) == maxFareCalendarDays {
+			return nil, NewInvalidArgumentError("价格日历最多查询 %d 天", maxFareCalendarDays)
+		}
+		dates = append(dates, day.Format(dateLayout))
+	}
+	return dates, nil
+}
+
+// 汇总当天各舱位等级的最低票价
+func summarizeFareCalendarDay(date string, itineraries []Itinerary) FareCalendarDay {
+	day := FareCalendarDay{Date: date, Flights: len(itineraries)}
+	for _, cabin := range []struct {
+		name  string
+		price *int64
+	}{
+		{EconomyClassName, &day.Economy},
+		{BusinessClassName, &day.Business},
+		{FirstClassName, &day.First},
+	} {
+		for _, itinerary := range itineraries {
+			fare, ok := itinerary.LowestFare(cabin.name)
+			if ok && (*cabin.price == 0 || fare.TotalPrice() < *cabin.price) {
+				*cabin.price = fare.TotalPrice()
+			}
+		}
+	}
+	return day
+}
+
+// 查询价格日历（按天查询国内单程航班, 返回每天各舱位等级的最低票价）
+// 某天查询失败时记录在当天的 Error 中, 全部失败时返回第一个错误
+func (c *Client) FareCalendar(ctx context.Context, req FareCalendarRequest) ([]FareCalendarDay, error) {
+	dates, err := calendarDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	searcher, err := c.fareSearcher()
+	if err != nil {
+		return nil, err
+	}
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultFareCalendarConcurrency
+	}
+	days := make([]FareCalendarDay, len(dates))
+	errs := make([]error, len(dates))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				itineraries, err := searcher.SearchMainLandFlights(ctx, DomesticSearchRequest{
+					Departure: req.Departure,
+					Arrival:   req.Arrival,
+					Date:      dates[index],
+					TripType:  TripTypeOneway,
+				})
+				if err != nil {
+					c.logger.Warnf("[Flight-Go]查询 %s 的航班失败, 错误原因: %v", dates[index], err)
+					days[index] = FareCalendarDay{Date: dates[index], Error: err.Error()}
+					errs[index] = err
+					continue
+				}
+				days[index] = summarizeFareCalendarDay(dates[index], req.Filter.Apply(itineraries))
+			}
+		}()
+	}
+	for index := range dates {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+	// 全部失败时（例如未知城市）返回第一个错误
+	for _, err := range errs {
+		if err == nil {
+			return days, nil
+		}
+	}
+	return nil, errs[0]
+}
+
+// 查询价格日历（使用临时客户端）
+func FareCalendar(ctx context.Context, req FareCalendarRequest, opts ...Option) ([]FareCalendarDay, error) {
+	return New(opts...).FareCalendar(ctx, req)
+}
@@ -0,0 +1,94 @@
+package flightgo
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFlexDateRange(t *testing.T) {
+	tests := []struct {
+		date       string
+		days       int
+		start, end string
+		valid      bool
+	}{
+		{"2019-11-15", 2, "2019-11-13", "2019-11-17", true},
+		{"2019-12-31", 1, "2019-12-30", "2020-01-01", true},
+		{"2019-11-15", 0, "2019-11-15", "2019-11-15", true},
+		{"2019-11-15", -1, "", "", false},
+		{"20191115", 2, "", "", false},
+	}
+	for _, tt := range tests {
+		start, end, err := FlexDateRange(tt.date, tt.days)
+		if (err == nil) != tt.valid || start != tt.start || end != tt.end {
+			t.Errorf("FlexDateRange(%s, %d) = %s %s %v, 期望 %s %s", tt.date, tt.days, start, end, err, tt.start, tt.end)
+		}
+	}
+}
+
+func TestCalendarDates(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		days       int
+		valid      bool
+	}{
+		{"跨月", "2019-11-29", "2019-12-02", 4, true},
+		{"同一天", "2019-11-15", "2019-11-15", 1, true},
+		{"结束日期早于开始日期", "2019-11-15", "2019-11-14", 0, false},
+		{"超过最多天数", "2019-01-01", "2019-12-31", 0, false},
+		{"日期格式错误", "2019/11/15", "2019-11-16", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dates, err := calendarDates(tt.start, tt.end)
+			if (err == nil) != tt.valid || len(dates) != tt.days {
+				t.Errorf("日期: %v（%v）, 期望 %d 天", dates, err, tt.days)
+			}
+			if err != nil && !IsErrorKind(err, ErrorInvalidArgument) {
+				t.Errorf("错误类型: %v, 期望参数错误", err)
+			}
+		})
+	}
+}
+
+func TestSummarizeFareCalendarDay(t *testing.T) {
+	day := summarizeFareCalendarDay("2019-11-15", []Itinerary{
+		testItinerary("CA1501", economy(880), business(2600)),
+		testItinerary("MU5138", economy(650)),
+	})
+	if day.Date != "2019-11-15" || day.Flights != 2 || day.Economy != 650 || day.Business != 2600 || day.First != 0 {
+		t.Errorf("价格日历: %+v", day)
+	}
+}
+
+func TestClientFareCalendar(t *testing.T) {
+	products := readCassetteBody(t, "schedule/db7cc40becea0ca6-001.json")
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 11-16 查询失败, 其他日期返回录制的航班
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), "2019-11-16") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(products))
+	}))
+	days, err := client.FareCalendar(context.Background(), FareCalendarRequest{
+		Departure: "北京", Arrival: "上海", StartDate: "2019-11-15", EndDate: "2019-11-17",
+	})
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(days) != 3 || days[0].Economy != 650 || days[1].Error == "" || days[2].Date != "2019-11-17" || days[2].Error != "" {
+		t.Errorf("价格日历: %+v", days)
+	}
+	// 全部失败时返回错误
+	if _, err := client.FareCalendar(context.Background(), FareCalendarRequest{
+		Departure: "不存在的城市", Arrival: "上海", StartDate: "2019-11-15", EndDate: "2019-11-16",
+	}); !IsErrorKind(err, ErrorUnknownCity) {
+		t.Errorf("全部失败的错误: %v, 期望未知城市", err)
+	}
+}
@@ -0,0 +1,238 @@
+package flightgo
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	cityNameCodeVersion string = "267040"
+	CityNameCodeURL            = "https://tce.alicdn.com/api/data.htm?ids=" + cityNameCodeVersion
+	// 本地缓存的有效期（过期后仍会使用, 但会提示更新）
+	cityNameCodeCacheTTL      = time.Hour * 24 * 30
+	cityNameCodeCacheFileName = "city_name_code.json"
+)
+
+// 城市名和城市代码的本地缓存
+type CityNameCodeCache struct {
+	Version   string            `json:"version"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	Cities    map[string]string `json:"cities"`
+}
+
+// 查询城市代码（本地数据中没有时返回空字符串）
+func (c *Client) lookupCityCode(cityName string) string {
+	c.cityNameCodeMu.Lock()
+	defer c.cityNameCodeMu.Unlock()
+	return c.loadedCityNameCode()[cityName]
+}
+
+// 城市名和城市代码的映射（首次调用时加载, 调用方需持有 cityNameCodeMu）
+func (c *Client) loadedCityNameCode() map[string]string {
+	if c.cityNameCode == nil {
+		c.cityNameCode = loadCityNameCodeData(c.logger)
+	}
+	return c.cityNameCode
+}
+
+// 机场名称去掉 "国际机场"、"机场" 后的简称（例如: 大兴国际机场 → 大兴）
+func airportShortName(name string) string {
+	for _, suffix := range []string{"国际机场", "机场"} {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return name
+}
+
+// 机场是否匹配名称（支持全称、简称和带城市名的写法, 例如: 大兴国际机场、大兴、北京大兴）
+func airportNameMatches(cityName string, airport Airport, query string) bool {
+	short := airportShortName(airport.Name)
+	for _, name := range []string{airport.Name, short, cityName + short, cityName + airport.Name, cityName + short + "机场"} {
+		if query == name {
+			return true
+		}
+	}
+	return false
+}
+
+// 查询机场（支持 IATA/ICAO 代码、机场名和城市名, 城市名返回该城市的全部机场）
+// 机场数据只包含内置的城市; 缓存中新增的城市没有机场数据, 返回以城市代码作为 IATA 代码的机场
+func (c *Client) LookupAirports(query string) ([]Airport, error) {
+	query = strings.TrimSpace(query)
+	withCity := func(city City, airport Airport) Airport {
+		airport.CityName = city.Name
+		return airport
+	}
+	var matched []Airport
+	for _, city := range bundledCityData {
+		for _, airport := range city.Airports {
+			if strings.EqualFold(query, airport.IATA) || strings.EqualFold(query, airport.ICAO) || airportNameMatches(city.Name, airport, query) {
+				matched = append(matched, withCity(city, airport))
+			}
+		}
+	}
+	switch {
+	case len(matched) == 1:
+		return matched, nil
+	case len(matched) > 1:
+		names := make([]string, 0, len(matched))
+		for _, airport := range matched {
+			names = append(names, airport.CityName+airport.Name+"("+airport.IATA+")")
+		}
+		return nil, NewInvalidArgumentError("%s 对应多个机场, 请使用机场代码指定: %s", query, strings.Join(names, ", "))
+	}
+	for _, city := range bundledCityData {
+		if city.Name == query && len(city.Airports) > 0 {
+			airports := make([]Airport, 0, len(city.Airports))
+			for _, airport := range city.Airports {
+				airports = append(airports, withCity(city, airport))
+			}
+			return airports, nil
+		}
+	}
+	if code := c.lookupCityCode(query); code != "" {
+		return []Airport{{CityName: query, IATA: code}}, nil
+	}
+	return nil, newUnknownCityError(query)
+}
+
+// 加载城市名和城市代码的数据（先加载内置数据, 再使用本地缓存覆盖）
+func loadCityNameCodeData(logger Logger) map[string]string {
+	cityNameCode := make(map[string]string)
+	for _, city := range bundledCityData {
+		cityNameCode[city.Name] = city.Code
+	}
+	cache, err := readCityNameCodeCache()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("[Flight-Go]读取城市数据缓存失败, 使用内置数据, 错误原因: %v", err)
+		}
+		return cityNameCode
+	}
+	for name, code := range cache.Cities {
+		cityNameCode[name] = code
+	}
+	if time.Since(cache.UpdatedAt) > cityNameCodeCacheTTL {
+		logger.Infof("[Flight-Go]城市数据缓存已过期（更新于 %s）, 可执行 cities update 更新", cache.UpdatedAt.Format("2006-01-02"))
+	}
+	return cityNameCode
+}
+
+// 本地缓存文件路径
+func cityNameCodeCachePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "flight-go", cityNameCodeCacheFileName), nil
+}
+
+// 读取本地缓存
+func readCityNameCodeCache() (*CityNameCodeCache, error) {
+	cachePath, err := cityNameCodeCachePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	cache := &CityNameCodeCache{}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// 写入本地缓存
+func writeCityNameCodeCache(cache *CityNameCodeCache) (string, error) {
+	cachePath, err := cityNameCodeCachePath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return cachePath, ioutil.WriteFile(cachePath, data, 0644)
+}
+
+// 从网络获取城市名和城市代码的数据
+func (c *Client) fetchCityNameCodeData(ctx context.Context) (map[string]string, error) {
+	dataResp, err := c.newRestClient().R().
+		SetContext(ctx).
+		SetHeader("user-agent", UserAgent).
+		Get(c.endpoints.CityNameCodeURL)
+	dataJson, err := parseJSONResponse("城市数据", dataResp, err)
+	if err != nil {
+		return nil, err
+	}
+	cities := make(map[string]string)
+	cityArray := dataJson.Get(cityNameCodeVersion).Get("value").Get("cityArray").Array()
+	if len(cityArray) < 2 {
+		return nil, newParseError("城市数据", "缺少 cityArray")
+	}
+	for _, cityGroup := range cityArray[1:] {
+		for _, cityData := range cityGroup.Get("tabdata").Array() {
+			for _, city := range cityData.Get("dd").Array() {
+				cities[city.Get("cityName").String()] = city.Get("cityCode").String()
+			}
+		}
+	}
+	return cities, nil
+}
+
+// 从网络更新城市数据的本地缓存（同时合并到当前客户端的城市数据中）, 返回缓存文件路径和城市数量
+func (c *Client) UpdateCityCache(ctx context.Context) (string, int, error) {
+	cities, err := c.fetchCityNameCodeData(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	c.cityNameCodeMu.Lock()
+	cityNameCode := c.loadedCityNameCode()
+	for name, code := range cities {
+		cityNameCode[name] = code
+	}
+	c.cityNameCodeMu.Unlock()
+	cachePath, err := writeCityNameCodeCache(&CityNameCodeCache{
+		Version:   cityNameCodeVersion,
+		UpdatedAt: time.Now(),
+		Cities:    cities,
+	})
+	return cachePath, len(cities), err
+}
+
+// 全部城市（内置数据附带机场信息, 缓存中新增的城市只有城市代码）
+func (c *Client) Cities() []City {
+	c.cityNameCodeMu.Lock()
+	defer c.cityNameCodeMu.Unlock()
+	cityNameCode := c.loadedCityNameCode()
+	cities := make([]City, 0, len(cityNameCode))
+	bundled := make(map[string]bool)
+	for _, city := range bundledCityData {
+		city.Code = cityNameCode[city.Name]
+		cities = append(cities, city)
+		bundled[city.Name] = true
+	}
+	var names []string
+	for name := range cityNameCode {
+		if !bundled[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cities = append(cities, City{Name: name, Code: cityNameCode[name]})
+	}
+	return cities
+}
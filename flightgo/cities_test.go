@@ -0,0 +1,87 @@
+package flightgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAirportShortName(t *testing.T) {
+	tests := []struct {
+		name  string
+		short string
+	}{
+		{"大兴国际机场", "大兴"},
+		{"南苑机场", "南苑"},
+		{"虹桥", "虹桥"},
+	}
+	for _, tt := range tests {
+		if short := airportShortName(tt.name); short != tt.short {
+			t.Errorf("airportShortName(%q) = %s, 期望 %s", tt.name, short, tt.short)
+		}
+	}
+}
+
+func TestClientLookupAirports(t *testing.T) {
+	tests := []struct {
+		query string
+		// 机场三字码
+		codes string
+		kind  ErrorKind
+	}{
+		{"PKX", "PKX", 0},
+		{"zbaa", "PEK", 0},
+		{"大兴国际机场", "PKX", 0},
+		{"北京大兴", "PKX", 0},
+		{" 虹桥 ", "SHA", 0},
+		// 城市名返回该城市的全部机场
+		{"北京", "PEK,PKX", 0},
+		{"广州", "CAN", 0},
+		{"不存在的城市", "", ErrorUnknownCity},
+	}
+	client := New()
+	for _, tt := range tests {
+		airports, err := client.LookupAirports(tt.query)
+		if tt.kind != 0 {
+			if !IsErrorKind(err, tt.kind) {
+				t.Errorf("LookupAirports(%q) 错误: %v, 期望类型 %d", tt.query, err, tt.kind)
+			}
+			continue
+		}
+		codes := make([]string, 0, len(airports))
+		for _, airport := range airports {
+			codes = append(codes, airport.IATA)
+		}
+		if err != nil || strings.Join(codes, ",") != tt.codes {
+			t.Errorf("LookupAirports(%q) = %v（%v）, 期望 %s", tt.query, codes, err, tt.codes)
+		}
+	}
+}
+
+func TestClientUpdateCityCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"267040":{"value":{"cityArray":[{},{"tabdata":[{"dd":[{"cityName":"漠河","cityCode":"OHE"},{"cityName":"北京","cityCode":"BJS"}]}]}]}}}`))
+	}))
+	t.Cleanup(server.Close)
+	client := New(WithHTTPClient(server.Client()), WithEndpoints(Endpoints{CityNameCodeURL: server.URL}))
+	if code := client.lookupCityCode("漠河"); code != "" {
+		t.Fatalf("更新前的城市代码: %s", code)
+	}
+	cachePath, count, err := client.UpdateCityCache(context.Background())
+	if err != nil || count != 2 {
+		t.Fatalf("更新失败: %d（%v）", count, err)
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Errorf("缓存文件: %v", err)
+	}
+	// 更新后当前客户端和新的客户端都使用新的城市数据
+	for _, c := range []*Client{client, New()} {
+		if code := c.lookupCityCode("漠河"); code != "OHE" {
+			t.Errorf("更新后的城市代码: %s, 期望 OHE", code)
+		}
+	}
+}
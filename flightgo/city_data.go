@@ -0,0 +1,96 @@
+package flightgo
+
+// 城市
+type City struct {
+	Name     string    `json:"name"`
+	Code     string    `json:"code"`
+	Airports []Airport `json:"airports,omitempty"`
+}
+
+// 内置的城市和机场数据（离线可用, 可通过 cities update 命令从网络更新城市代码）
+var bundledCityData = []City{
+	{Name: "北京", Code: "BJS", Airports: []Airport{
+		{Name: "首都国际机场", IATA: "PEK", ICAO: "ZBAA"},
+		{Name: "大兴国际机场", IATA: "PKX", ICAO: "ZBAD"},
+	}},
+	{Name: "上海", Code: "SHA", Airports: []Airport{
+		{Name: "浦东国际机场", IATA: "PVG", ICAO: "ZSPD"},
+		{Name: "虹桥国际机场", IATA: "SHA", ICAO: "ZSSS"},
+	}},
+	{Name: "广州", Code: "CAN", Airports: []Airport{{Name: "白云国际机场", IATA: "CAN", ICAO: "ZGGG"}}},
+	{Name: "深圳", Code: "SZX", Airports: []Airport{{Name: "宝安国际机场", IATA: "SZX", ICAO: "ZGSZ"}}},
+	{Name: "成都", Code: "CTU", Airports: []Airport{
+		{Name: "双流国际机场", IATA: "CTU", ICAO: "ZUUU"},
+		{Name: "天府国际机场", IATA: "TFU", ICAO: "ZUTF"},
+	}},
+	{Name: "重庆", Code: "CKG", Airports: []Airport{{Name: "江北国际机场", IATA: "CKG", ICAO: "ZUCK"}}},
+	{Name: "杭州", Code: "HGH", Airports: []Airport{{Name: "萧山国际机场", IATA: "HGH", ICAO: "ZSHC"}}},
+	{Name: "南京", Code: "NKG", Airports: []Airport{{Name: "禄口国际机场", IATA: "NKG", ICAO: "ZSNJ"}}},
+	{Name: "武汉", Code: "WUH", Airports: []Airport{{Name: "天河国际机场", IATA: "WUH", ICAO: "ZHHH"}}},
+	{Name: "西安", Code: "SIA", Airports: []Airport{{Name: "咸阳国际机场", IATA: "XIY", ICAO: "ZLXY"}}},
+	{Name: "昆明", Code: "KMG", Airports: []Airport{{Name: "长水国际机场", IATA: "KMG", ICAO: "ZPPP"}}},
+	{Name: "厦门", Code: "XMN", Airports: []Airport{{Name: "高崎国际机场", IATA: "XMN", ICAO: "ZSAM"}}},
+	{Name: "长沙", Code: "CSX", Airports: []Airport{{Name: "黄花国际机场", IATA: "CSX", ICAO: "ZGHA"}}},
+	{Name: "青岛", Code: "TAO", Airports: []Airport{{Name: "胶东国际机场", IATA: "TAO", ICAO: "ZSQD"}}},
+	{Name: "大连", Code: "DLC", Airports: []Airport{{Name: "周水子国际机场", IATA: "DLC", ICAO: "ZYTL"}}},
+	{Name: "沈阳", Code: "SHE", Airports: []Airport{{Name: "桃仙国际机场", IATA: "SHE", ICAO: "ZYTX"}}},
+	{Name: "哈尔滨", Code: "HRB", Airports: []Airport{{Name: "太平国际机场", IATA: "HRB", ICAO: "ZYHB"}}},
+	{Name: "长春", Code: "CGQ", Airports: []Airport{{Name: "龙嘉国际机场", IATA: "CGQ", ICAO: "ZYCC"}}},
+	{Name: "天津", Code: "TSN", Airports: []Airport{{Name: "滨海国际机场", IATA: "TSN", ICAO: "ZBTJ"}}},
+	{Name: "郑州", Code: "CGO", Airports: []Airport{{Name: "新郑国际机场", IATA: "CGO", ICAO: "ZHCC"}}},
+	{Name: "济南", Code: "TNA", Airports: []Airport{{Name: "遥墙国际机场", IATA: "TNA", ICAO: "ZSJN"}}},
+	{Name: "合肥", Code: "HFE", Airports: []Airport{{Name: "新桥国际机场", IATA: "HFE", ICAO: "ZSOF"}}},
+	{Name: "南昌", Code: "KHN", Airports: []Airport{{Name: "昌北国际机场", IATA: "KHN", ICAO: "ZSCN"}}},
+	{Name: "福州", Code: "FOC", Airports: []Airport{{Name: "长乐国际机场", IATA: "FOC", ICAO: "ZSFZ"}}},
+	{Name: "南宁", Code: "NNG", Airports: []Airport{{Name: "吴圩国际机场", IATA: "NNG", ICAO: "ZGNN"}}},
+	{Name: "贵阳", Code: "KWE", Airports: []Airport{{Name: "龙洞堡国际机场", IATA: "KWE", ICAO: "ZUGY"}}},
+	{Name: "海口", Code: "HAK", Airports: []Airport{{Name: "美兰国际机场", IATA: "HAK", ICAO: "ZJHK"}}},
+	{Name: "三亚", Code: "SYX", Airports: []Airport{{Name: "凤凰国际机场", IATA: "SYX", ICAO: "ZJSY"}}},
+	{Name: "乌鲁木齐", Code: "URC", Airports: []Airport{{Name: "地窝堡国际机场", IATA: "URC", ICAO: "ZWWW"}}},
+	{Name: "兰州", Code: "LHW", Airports: []Airport{{Name: "中川国际机场", IATA: "LHW", ICAO: "ZLLL"}}},
+	{Name: "西宁", Code: "XNN", Airports: []Airport{{Name: "曹家堡国际机场", IATA: "XNN", ICAO: "ZLXN"}}},
+	{Name: "银川", Code: "INC", Airports: []Airport{{Name: "河东国际机场", IATA: "INC", ICAO: "ZLIC"}}},
+	{Name: "呼和浩特", Code: "HET", Airports: []Airport{{Name: "白塔国际机场", IATA: "HET", ICAO: "ZBHH"}}},
+	{Name: "太原", Code: "TYN", Airports: []Airport{{Name: "武宿国际机场", IATA: "TYN", ICAO: "ZBYN"}}},
+	{Name: "石家庄", Code: "SJW", Airports: []Airport{{Name: "正定国际机场", IATA: "SJW", ICAO: "ZBSJ"}}},
+	{Name: "拉萨", Code: "LXA", Airports: []Airport{{Name: "贡嘎国际机场", IATA: "LXA", ICAO: "ZULS"}}},
+	{Name: "宁波", Code: "NGB", Airports: []Airport{{Name: "栎社国际机场", IATA: "NGB", ICAO: "ZSNB"}}},
+	{Name: "温州", Code: "WNZ", Airports: []Airport{{Name: "龙湾国际机场", IATA: "WNZ", ICAO: "ZSWZ"}}},
+	{Name: "无锡", Code: "WUX", Airports: []Airport{{Name: "苏南硕放国际机场", IATA: "WUX", ICAO: "ZSWX"}}},
+	{Name: "常州", Code: "CZX", Airports: []Airport{{Name: "奔牛国际机场", IATA: "CZX", ICAO: "ZSCG"}}},
+	{Name: "扬州", Code: "YTY", Airports: []Airport{{Name: "扬州泰州国际机场", IATA: "YTY", ICAO: "ZSYA"}}},
+	{Name: "徐州", Code: "XUZ", Airports: []Airport{{Name: "观音国际机场", IATA: "XUZ", ICAO: "ZSXZ"}}},
+	{Name: "珠海", Code: "ZUH", Airports: []Airport{{Name: "金湾机场", IATA: "ZUH", ICAO: "ZGSD"}}},
+	{Name: "揭阳", Code: "SWA", Airports: []Airport{{Name: "潮汕国际机场", IATA: "SWA", ICAO: "ZGOW"}}},
+	{Name: "湛江", Code: "ZHA", Airports: []Airport{{Name: "吴川国际机场", IATA: "ZHA", ICAO: "ZGZJ"}}},
+	{Name: "桂林", Code: "KWL", Airports: []Airport{{Name: "两江国际机场", IATA: "KWL", ICAO: "ZGKL"}}},
+	{Name: "北海", Code: "BHY", Airports: []Airport{{Name: "福成机场", IATA: "BHY", ICAO: "ZGBH"}}},
+	{Name: "丽江", Code: "LJG", Airports: []Airport{{Name: "三义国际机场", IATA: "LJG", ICAO: "ZPLJ"}}},
+	{Name: "大理", Code: "DLU", Airports: []Airport{{Name: "大理机场", IATA: "DLU", ICAO: "ZPDL"}}},
+	{Name: "西双版纳", Code: "JHG", Airports: []Airport{{Name: "嘎洒国际机场", IATA: "JHG", ICAO: "ZPJH"}}},
+	{Name: "遵义", Code: "ZYI", Airports: []Airport{{Name: "新舟机场", IATA: "ZYI", ICAO: "ZUZY"}}},
+	{Name: "绵阳", Code: "MIG", Airports: []Airport{{Name: "南郊机场", IATA: "MIG", ICAO: "ZUMY"}}},
+	{Name: "九寨沟", Code: "JZH", Airports: []Airport{{Name: "九黄机场", IATA: "JZH", ICAO: "ZUJZ"}}},
+	{Name: "烟台", Code: "YNT", Airports: []Airport{{Name: "蓬莱国际机场", IATA: "YNT", ICAO: "ZSYT"}}},
+	{Name: "威海", Code: "WEH", Airports: []Airport{{Name: "大水泊国际机场", IATA: "WEH", ICAO: "ZSWH"}}},
+	{Name: "临沂", Code: "LYI", Airports: []Airport{{Name: "启阳国际机场", IATA: "LYI", ICAO: "ZSLY"}}},
+	{Name: "泉州", Code: "JJN", Airports: []Airport{{Name: "晋江国际机场", IATA: "JJN", ICAO: "ZSQZ"}}},
+	{Name: "黄山", Code: "TXN", Airports: []Airport{{Name: "屯溪国际机场", IATA: "TXN", ICAO: "ZSTX"}}},
+	{Name: "舟山", Code: "HSN", Airports: []Airport{{Name: "普陀山机场", IATA: "HSN", ICAO: "ZSZS"}}},
+	{Name: "赣州", Code: "KOW", Airports: []Airport{{Name: "黄金机场", IATA: "KOW", ICAO: "ZSGZ"}}},
+	{Name: "张家界", Code: "DYG", Airports: []Airport{{Name: "荷花国际机场", IATA: "DYG", ICAO: "ZGDY"}}},
+	{Name: "宜昌", Code: "YIH", Airports: []Airport{{Name: "三峡机场", IATA: "YIH", ICAO: "ZHYC"}}},
+	{Name: "洛阳", Code: "LYA", Airports: []Airport{{Name: "北郊机场", IATA: "LYA", ICAO: "ZHLY"}}},
+	{Name: "秦皇岛", Code: "BPE", Airports: []Airport{{Name: "北戴河国际机场", IATA: "BPE", ICAO: "ZBDH"}}},
+	{Name: "包头", Code: "BAV", Airports: []Airport{{Name: "东河机场", IATA: "BAV", ICAO: "ZBOW"}}},
+	{Name: "大庆", Code: "DQA", Airports: []Airport{{Name: "萨尔图机场", IATA: "DQA", ICAO: "ZYDQ"}}},
+	{Name: "延吉", Code: "YNJ", Airports: []Airport{{Name: "朝阳川国际机场", IATA: "YNJ", ICAO: "ZYYJ"}}},
+	{Name: "敦煌", Code: "DNH", Airports: []Airport{{Name: "莫高国际机场", IATA: "DNH", ICAO: "ZLDH"}}},
+	{Name: "喀什", Code: "KHG", Airports: []Airport{{Name: "喀什国际机场", IATA: "KHG", ICAO: "ZWSH"}}},
+	{Name: "香港", Code: "HKG", Airports: []Airport{{Name: "香港国际机场", IATA: "HKG", ICAO: "VHHH"}}},
+	{Name: "澳门", Code: "MFM", Airports: []Airport{{Name: "澳门国际机场", IATA: "MFM", ICAO: "VMMC"}}},
+	{Name: "台北", Code: "TPE", Airports: []Airport{
+		{Name: "桃园国际机场", IATA: "TPE", ICAO: "RCTP"},
+		{Name: "松山机场", IATA: "TSA", ICAO: "RCSS"},
+	}},
+}
@@ -0,0 +1,284 @@
+// Package flightgo 提供国内/国际机票价格、航班动态和机场进出港信息的查询接口。
+//
+// 所有查询都接受 context.Context, 可通过 Option 自定义 HTTP 客户端、日志和接口地址:
+//
+//	client := flightgo.New(flightgo.WithHTTPClient(httpClient), flightgo.WithLogger(logger))
+//	itineraries, err := client.SearchDomestic(ctx, flightgo.DomesticSearchRequest{
+//		Departure: "北京", Arrival: "上海", Date: "2019-11-15",
+//	})
+//
+// 查询失败时返回 *Error, 可通过 IsErrorKind 判断错误类型。
+package flightgo
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty"
+)
+
+// 日志接口（*logrus.Entry 和 *logrus.Logger 均可直接使用）
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// 默认不输出日志
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Infof(format string, args ...interface{})  {}
+func (nopLogger) Warnf(format string, args ...interface{})  {}
+func (nopLogger) Errorf(format string, args ...interface{}) {}
+
+// 查询客户端
+type Client struct {
+	httpClient *http.Client
+	logger     Logger
+	endpoints  Endpoints
+	provider   string
+	// 已构造的数据源（同一数据源只构造一次）
+	providersMu sync.Mutex
+	providers   map[string]Provider
+	// 城市名和城市代码的映射（首次查询时加载）
+	cityNameCodeMu sync.Mutex
+	cityNameCode   map[string]string
+	// 补充的航班状态码映射和未知状态码的处理函数
+	statusCodes          map[int64]FlightStatusCode
+	unknownStatusHandler func(UnknownFlightStatus)
+}
+
+// 客户端配置项
+type Option func(*Client)
+
+// 使用自定义的 HTTP 客户端（代理、超时、录制/回放等）
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// 使用自定义的日志
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// 使用自定义的接口地址（未设置的字段使用默认值）
+func WithEndpoints(endpoints Endpoints) Option {
+	return func(c *Client) {
+		c.endpoints = endpoints
+	}
+}
+
+// 指定数据源（默认使用第一个支持该查询的数据源）
+func WithProvider(name string) Option {
+	return func(c *Client) {
+		c.provider = name
+	}
+}
+
+// 补充或覆盖航班状态码映射（例如接口新增的状态码）
+func WithFlightStatusCodes(codes map[int64]FlightStatusCode) Option {
+	return func(c *Client) {
+		c.statusCodes = codes
+	}
+}
+
+// 遇到未知的航班状态码时调用（例如记录到本地日志, 便于之后补充映射）
+func WithUnknownFlightStatusHandler(handler func(UnknownFlightStatus)) Option {
+	return func(c *Client) {
+		c.unknownStatusHandler = handler
+	}
+}
+
+// 新建查询客户端
+func New(opts ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{},
+		logger:     nopLogger{},
+		endpoints:  DefaultEndpoints(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.endpoints = c.endpoints.Expand()
+	return c
+}
+
+// 新建 HTTP 请求客户端
+func (c *Client) newRestClient() *resty.Client {
+	return resty.NewWithClient(c.httpClient)
+}
+
+// 国内航班查询请求
+type DomesticSearchRequest struct {
+	// 出发城市和到达城市（城市名, 例如: 北京）
+	Departure string
+	Arrival   string
+	// 出发日期（格式: YYYY-MM-DD）
+	Date string
+	// 返程日期（格式: YYYY-MM-DD, 仅 SearchRoundTrip 使用）
+	ReturnDate string
+	// 行程类型（默认: Oneway）
+	TripType string
+}
+
+// 国际航班查询请求
+type InternationalSearchRequest struct {
+	// 出发地和到达地（国家或城市名, 例如: 东京）
+	Departure string
+	Arrival   string
+	// 出发日期（格式: YYYY-MM-DD）
+	Date string
+	// 舱位等级（经济舱，超级经济舱，商务/头等舱，商务舱，公务舱，头等舱; 默认: 经济舱）
+	Cabin string
+	// 轮询结果的间隔（默认: 1 秒）
+	PollInterval time.Duration
+	// 整个查询的最长时间（默认: 1 分钟）, 超过后不再轮询, 返回已加载的结果
+	Timeout time.Duration
+	// 行程确定后调用（之后加载的结果中没有新的票价, 或加载结束）, 每个行程只调用一次, 用于边加载边展示;
+	// 之后再出现的票价只合并到返回的结果中, 不会再次调用
+	OnItineraries func(itineraries []Itinerary)
+}
+
+// 进出港类别
+const (
+	DirectionDeparture string = "dep"
+	DirectionArrival   string = "arr"
+)
+
+// 机场进出港查询请求
+type AirportBoardRequest struct {
+	// 城市名、机场名或机场代码（例如: 北京、北京大兴、PKX、ZBAD）, 城市名会查询该城市的全部机场
+	Airport string
+	// 机场三字码（由 Client.AirportBoard 根据 Airport 解析后填写, 为空时数据源按城市代码查询）
+	AirportCode string
+	// 进出港类别（dep: 出港; arr: 进港）
+	Direction string
+	// 日期（格式: YYYY-MM-DD; 为空时不限制）, 只保留计划时间（北京时间）在该日期的航班
+	// 数据源接口没有日期参数, 只能从接口返回的航班中过滤, 不能查询接口没有返回的日期
+	Date string
+	// 时间范围（零值表示不限制）, 计划时间或实际/预计时间在范围内的航班都会保留
+	Since time.Time
+	Until time.Time
+	// 每页数量（默认: 15）, 会自动翻页直到取完全部航班
+	PageSize int
+}
+
+// 查询国内航班
+func (c *Client) SearchDomestic(ctx context.Context, req DomesticSearchRequest) ([]Itinerary, error) {
+	if req.TripType == "" {
+		req.TripType = TripTypeOneway
+	}
+	searcher, err := c.fareSearcher()
+	if err != nil {
+		return nil, err
+	}
+	return searcher.SearchMainLandFlights(ctx, req)
+}
+
+// 查询国际航班
+func (c *Client) SearchInternational(ctx context.Context, req InternationalSearchRequest) ([]Itinerary, error) {
+	if req.PollInterval < 0 || req.Timeout < 0 {
+		return nil, NewInvalidArgumentError("轮询间隔和查询时间不能为负数")
+	}
+	searcher, err := c.fareSearcher()
+	if err != nil {
+		return nil, err
+	}
+	return searcher.SearchOverSeaFlights(ctx, req)
+}
+
+// 查询航班动态（日期格式: YYYYMMDD）
+func (c *Client) FlightStatus(ctx context.Context, flightNumber, date string) ([]FlightStatus, error) {
+	searcher, err := c.flightStatusSearcher()
+	if err != nil {
+		return nil, err
+	}
+	return searcher.SearchFlightInfo(ctx, flightNumber, date)
+}
+
+// 查询机场进出港信息
+func (c *Client) AirportBoard(ctx context.Context, req AirportBoardRequest) ([]BoardEntry, error) {
+	searcher, err := c.airportBoardSearcher()
+	if err != nil {
+		return nil, err
+	}
+	if req.Date != "" {
+		if _, err := time.Parse(AirportBoardDateLayout, req.Date); err != nil {
+			return nil, NewInvalidArgumentError("日期格式错误（需要 YYYY-MM-DD）: %s", req.Date)
+		}
+	}
+	airports, err := c.LookupAirports(req.Airport)
+	if err != nil {
+		return nil, err
+	}
+	// 查询城市的全部机场时合并各机场的航班, 某个机场查询失败时跳过该机场
+	entries := make([]BoardEntry, 0)
+	var lastErr error
+	for _, airport := range airports {
+		airportReq := req
+		airportReq.AirportCode = airport.IATA
+		airportEntries, err := searcher.SearchAirportInfo(ctx, airportReq)
+		if err != nil {
+			if len(airports) == 1 || ctx.Err() != nil {
+				return nil, err
+			}
+			c.logger.Warnf("[Flight-Go]查询机场 %s%s(%s) 进出港信息失败, 错误原因: %v", airport.CityName, airport.Name, airport.IATA, err)
+			lastErr = err
+			continue
+		}
+		for i := range airportEntries {
+			airportEntries[i].setLocalAirport(airport)
+		}
+		entries = append(entries, airportEntries...)
+	}
+	if len(entries) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	if len(airports) > 1 {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].ScheduledTime.Before(entries[j].ScheduledTime.Time)
+		})
+	}
+	return req.filterEntries(entries), nil
+}
+
+// 查询城市代码（先查本地城市数据, 没有时通过数据源查询）
+func (c *Client) CityCode(ctx context.Context, cityName string) (string, error) {
+	if code := c.lookupCityCode(cityName); code != "" {
+		return code, nil
+	}
+	lookup, err := c.cityCodeLookup()
+	if err != nil {
+		return "", err
+	}
+	return lookup.GetCityCode(ctx, cityName)
+}
+
+// 查询国内航班（使用临时客户端）
+func SearchDomestic(ctx context.Context, req DomesticSearchRequest, opts ...Option) ([]Itinerary, error) {
+	return New(opts...).SearchDomestic(ctx, req)
+}
+
+// 查询国际航班（使用临时客户端）
+func SearchInternational(ctx context.Context, req InternationalSearchRequest, opts ...Option) ([]Itinerary, error) {
+	return New(opts...).SearchInternational(ctx, req)
+}
+
+// 查询航班动态（使用临时客户端, 与 FlightStatus 类型同名, 故加 Search 前缀）
+func SearchFlightStatus(ctx context.Context, flightNumber, date string, opts ...Option) ([]FlightStatus, error) {
+	return New(opts...).FlightStatus(ctx, flightNumber, date)
+}
+
+// 查询机场进出港信息（使用临时客户端）
+func AirportBoard(ctx context.Context, req AirportBoardRequest, opts ...Option) ([]BoardEntry, error) {
+	return New(opts...).AirportBoard(ctx, req)
+}
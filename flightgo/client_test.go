@@ -0,0 +1,254 @@
+package flightgo
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// 使用本地测试服务作为数据源接口的客户端
+func newTestClient(t *testing.T, handler http.Handler, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	opts = append([]Option{
+		WithHTTPClient(server.Client()),
+		WithEndpoints(Endpoints{CtripBaseURL: server.URL, VariFlightBaseURL: server.URL}),
+	}, opts...)
+	return New(opts...)
+}
+
+// 返回录制的接口响应
+func serveCassette(t *testing.T, name string) http.HandlerFunc {
+	body := readCassetteBody(t, name)
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestClientSearchDomestic(t *testing.T) {
+	products := readCassetteBody(t, "schedule/db7cc40becea0ca6-001.json")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/itinerary/api/12808/products", func(w http.ResponseWriter, r *http.Request) {
+		var payload FlightTablePayload
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("请求参数不是合法的 JSON: %v", err)
+		}
+		if r.Method != http.MethodPost || len(payload.APParams) != 1 || payload.APParams[0].DCity != "BJS" ||
+			payload.APParams[0].ACity != "SHA" || payload.APParams[0].Date != "2019-11-15" || payload.FlightWay != "Oneway" {
+			t.Errorf("请求: %s %s", r.Method, body)
+		}
+		_, _ = w.Write([]byte(products))
+	})
+	client := newTestClient(t, mux)
+	itineraries, err := client.SearchDomestic(context.Background(), DomesticSearchRequest{Departure: "北京", Arrival: "上海", Date: "2019-11-15"})
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(itineraries) != 2 || itineraries[0].Legs[0].FlightNumber != "CA1501" || itineraries[1].Legs[0].FlightNumber != "MU5138" {
+		t.Fatalf("行程: %+v", itineraries)
+	}
+}
+
+func TestClientSearchDomesticCityCodeLookup(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/international/search/api/poi/search", serveCassette(t, "oversea/78a8bbcd4a814358-001.json"))
+	mux.HandleFunc("/itinerary/api/12808/products", func(w http.ResponseWriter, r *http.Request) {
+		var payload FlightTablePayload
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		// 本地城市数据中没有的城市使用接口查询到的城市代码
+		if len(payload.APParams) != 1 || payload.APParams[0].DCity != "BJS" || payload.APParams[0].ACity != "TYO" {
+			t.Errorf("请求: %s", body)
+		}
+		_, _ = w.Write([]byte(`{"data":{"routeList":[]}}`))
+	})
+	client := newTestClient(t, mux)
+	if _, err := client.SearchDomestic(context.Background(), DomesticSearchRequest{Departure: "北京", Arrival: "东京", Date: "2019-11-20"}); err != nil {
+		t.Errorf("查询失败: %v", err)
+	}
+}
+
+func TestClientSearchDomesticErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		departure string
+		status    int
+		body      string
+		kind      ErrorKind
+	}{
+		// 本地城市数据中没有时通过城市代码接口查询
+		{"未知的城市", "不存在的城市", http.StatusOK, `{"Data":[]}`, ErrorUnknownCity},
+		{"被拦截", "北京", http.StatusForbidden, "", ErrorAntiBotBlocked},
+		{"返回验证页面", "北京", http.StatusOK, "<html><body>验证</body></html>", ErrorAntiBotBlocked},
+		{"服务端错误", "北京", http.StatusInternalServerError, "", ErrorNetwork},
+		{"数据为空", "北京", http.StatusOK, " ", ErrorUpstreamEmpty},
+		{"不是合法的 JSON", "北京", http.StatusOK, "{\"data\":", ErrorParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			_, err := client.SearchDomestic(context.Background(), DomesticSearchRequest{Departure: tt.departure, Arrival: "上海", Date: "2019-11-15"})
+			if !IsErrorKind(err, tt.kind) {
+				t.Errorf("错误: %v, 期望类型 %d", err, tt.kind)
+			}
+		})
+	}
+}
+
+func TestClientSearchInternational(t *testing.T) {
+	pois := map[string]string{
+		"北京": readCassetteBody(t, "oversea/72aae9cb3ad6ef88-001.json"),
+		"东京": readCassetteBody(t, "oversea/78a8bbcd4a814358-001.json"),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/international/search/api/poi/search", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pois[r.URL.Query().Get("key")]))
+	})
+	mux.HandleFunc("/international/search/oneway-BJS-TYO", serveCassette(t, "oversea/77ec2672f04426f1-001.json"))
+	mux.HandleFunc("/international/search/api/search/batchSearch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("sign") == "" || r.Header.Get("transactionid") != "0f1e2d3c4b5a69788796a5b4c3d2e1f0" {
+			t.Errorf("缺少 sign 或 transactionid: %v", r.Header)
+		}
+		serveCassette(t, "oversea/e03e8a661aa1a154-001.json")(w, r)
+	})
+	mux.HandleFunc("/international/search/api/search/pull/a1b2c3d4", serveCassette(t, "oversea/28f6f50a4d396fae-001.json"))
+	client := newTestClient(t, mux)
+	loaded := make([]int, 0)
+	itineraries, err := client.SearchInternational(context.Background(), InternationalSearchRequest{
+		Departure:    "北京",
+		Arrival:      "东京",
+		Date:         "2019-11-20",
+		PollInterval: time.Millisecond,
+		OnItineraries: func(itineraries []Itinerary) {
+			loaded = append(loaded, len(itineraries))
+		},
+	})
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	// pull 重复返回了 batchSearch 中的 CA925, 按航段去重后只保留一个
+	if len(itineraries) != 2 || itineraries[1].Legs[0].FlightNumber != "KE856" {
+		t.Fatalf("行程数量: %d, 期望 2", len(itineraries))
+	}
+	// pull 中没有 CA925 的新票价, CA925 在 pull 之后确定; KE856 在加载结束时确定
+	if len(loaded) != 2 || loaded[0] != 1 || loaded[1] != 1 {
+		t.Errorf("每次确定的行程数: %v, 期望 [1 1]", loaded)
+	}
+	if prices := farePrices(itineraries[0].Fares); !equalPrices(prices, []int64{1850, 2300}) {
+		t.Errorf("CA925 的票价: %v", prices)
+	}
+	if fare := itineraries[0].Fares[0]; fare.Cabin.Code != "y_s" || fare.Price != 1850 {
+		t.Errorf("票价: %+v", fare)
+	}
+}
+
+func TestClientFlightStatus(t *testing.T) {
+	body := readCassetteBody(t, "code/02c875756c8e35e7-001.json")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/adsb/index/advancedSearch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.FormValue("searchText") != "CA1501" || r.FormValue("searchDate") != "20191115" {
+			t.Errorf("请求: %s %v", r.Method, r.Form)
+		}
+		_, _ = w.Write([]byte(body))
+	})
+	statuses, err := newTestClient(t, mux).FlightStatus(context.Background(), "CA1501", "20191115")
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].StatusCode != 2 {
+		t.Fatalf("航班动态: %+v", statuses)
+	}
+}
+
+func TestClientAirportBoard(t *testing.T) {
+	mux := http.NewServeMux()
+	for path, cassette := range map[string]string{
+		"/adsb/airport/api/departures": "airport-dep/ca5cb1bb9de38622-001.json",
+		"/adsb/airport/api/arrival":    "airport-arr/48466dae8b50418e-001.json",
+	} {
+		body := readCassetteBody(t, cassette)
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("iata") != "CAN" {
+				t.Errorf("请求参数: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(body))
+		})
+	}
+	client := newTestClient(t, mux)
+	tests := []struct {
+		req   AirportBoardRequest
+		count int
+		kind  ErrorKind
+	}{
+		{AirportBoardRequest{Airport: "广州", Direction: DirectionDeparture}, 3, 0},
+		{AirportBoardRequest{Airport: "广州", Direction: DirectionArrival}, 2, 0},
+		{AirportBoardRequest{Airport: "广州", Direction: "all"}, 0, ErrorInvalidArgument},
+		{AirportBoardRequest{Airport: "不存在的城市", Direction: DirectionDeparture}, 0, ErrorUnknownCity},
+	}
+	for _, tt := range tests {
+		entries, err := client.AirportBoard(context.Background(), tt.req)
+		if tt.kind != 0 {
+			if !IsErrorKind(err, tt.kind) {
+				t.Errorf("%+v 错误: %v, 期望类型 %d", tt.req, err, tt.kind)
+			}
+			continue
+		}
+		if err != nil || len(entries) != tt.count {
+			t.Errorf("%+v 航班数量: %d（%v）, 期望 %d", tt.req, len(entries), err, tt.count)
+		}
+	}
+}
+
+func TestClientContextCanceled(t *testing.T) {
+	client := newTestClient(t, serveCassette(t, "schedule/db7cc40becea0ca6-001.json"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.SearchDomestic(ctx, DomesticSearchRequest{Departure: "北京", Arrival: "上海", Date: "2019-11-15"}); !IsErrorKind(err, ErrorNetwork) {
+		t.Errorf("取消查询的错误: %v, 期望网络错误", err)
+	}
+}
+
+// 只支持城市代码查询的数据源
+type testCityCodeProvider struct{}
+
+func (p testCityCodeProvider) Name() string {
+	return "test-city"
+}
+
+func (p testCityCodeProvider) GetCityCode(ctx context.Context, cityName string) (string, error) {
+	return "TST", nil
+}
+
+func TestClientProviders(t *testing.T) {
+	constructed := 0
+	RegisterProvider("test-city", func(c *Client) Provider {
+		constructed++
+		return testCityCodeProvider{}
+	})
+	client := New(WithProvider("test-city"))
+	// 本地城市数据中没有时通过数据源查询, 数据源只构造一次
+	for i := 0; i < 2; i++ {
+		if code, err := client.CityCode(context.Background(), "测试城市"); err != nil || code != "TST" {
+			t.Errorf("城市代码: %s（%v）, 期望 TST", code, err)
+		}
+	}
+	if constructed != 1 {
+		t.Errorf("数据源构造了 %d 次, 期望 1 次", constructed)
+	}
+	// 指定的数据源不支持该查询或不存在
+	if _, err := client.FlightStatus(context.Background(), "CA1501", "20191115"); !IsErrorKind(err, ErrorInvalidArgument) {
+		t.Errorf("不支持的查询: %v", err)
+	}
+	if _, err := New(WithProvider("unknown")).FlightStatus(context.Background(), "CA1501", "20191115"); !IsErrorKind(err, ErrorInvalidArgument) {
+		t.Errorf("未知的数据源: %v", err)
+	}
+}
@@ -0,0 +1,446 @@
+package flightgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+
+	"github.com/go-resty/resty"
+	"github.com/tidwall/gjson"
+)
+
+type AirportParams struct {
+	ACity     string `json:"acity"`
+	ACityName string `json:"acityname"`
+	Date      string `json:"date"`
+	DCity     string `json:"dcity"`
+	DCityName string `json:"dcityname"`
+}
+
+type FlightTablePayload struct {
+	APParams    []AirportParams `json:"airportParams"`
+	Army        bool            `json:"army"`
+	ClassType   string          `json:"classType"`
+	FlightWay   string          `json:"flightWay"`
+	HasBaby     bool            `json:"hasBaby"`
+	HasChild    bool            `json:"hasChild"`
+	Params      []AirportParams `json:"params"`
+	SearchIndex int             `json:"searchIndex"`
+}
+
+type CtripCrawler struct {
+	RestClient *resty.Client
+	client     *Client
+}
+
+func NewCtripCrawler(client *Client) *CtripCrawler {
+	ctrip := &CtripCrawler{client: client}
+	ctrip.initCtripCrawler()
+	return ctrip
+}
+
+func init() {
+	RegisterProvider(CtripProviderName, func(c *Client) Provider {
+		return NewCtripCrawler(c)
+	})
+}
+
+// 数据源名称
+func (c *CtripCrawler) Name() string {
+	return CtripProviderName
+}
+
+// 初始化
+func (c *CtripCrawler) initCtripCrawler() {
+	c.RestClient = c.client.newRestClient()
+}
+
+// 构造请求参数（多段行程每段都需要传入, searchIndex 为当前查询的段序号, 从 1 开始; 本地城市数据中没有的城市通过数据源查询城市代码）
+func (c *CtripCrawler) getFlightTablePayload(ctx context.Context, segments []SegmentRequest, classType, tripType string, searchIndex int) (string, error) {
+	payload := FlightTablePayload{
+		APParams:    make([]AirportParams, 0),
+		Army:        false,
+		ClassType:   classType,
+		FlightWay:   tripType,
+		HasBaby:     false,
+		HasChild:    false,
+		SearchIndex: searchIndex,
+	}
+	for _, segment := range segments {
+		departureCityCode, err := c.client.CityCode(ctx, segment.Departure)
+		if err != nil {
+			return "", err
+		}
+		arriveCityCode, err := c.client.CityCode(ctx, segment.Arrival)
+		if err != nil {
+			return "", err
+		}
+		airportParams := AirportParams{
+			ACity:     arriveCityCode,
+			ACityName: segment.Arrival,
+			Date:      segment.Date,
+			DCity:     departureCityCode,
+			DCityName: segment.Departure,
+		}
+		payload.APParams = append(payload.APParams, airportParams)
+		payload.Params = append(payload.Params, airportParams)
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// 解析国内航班数据
+func (c *CtripCrawler) parseMainLandItineraries(tableJson gjson.Result) []Itinerary {
+	itineraries := make([]Itinerary, 0)
+	// 航班数据
+	flightRouteList := tableJson.Get("data").Get("routeList").Array()
+	for _, flightInfoHeader := range flightRouteList {
+		// 判断线路类型 Flight 飞行；FlightTrain 空地联运
+		tripType := flightInfoHeader.Get("routeType").String()
+		if tripType != "Flight" {
+			continue
+		}
+		itinerary := Itinerary{Legs: make([]Leg, 0)}
+		for _, flightInfo := range flightInfoHeader.Get("legs").Array() {
+			itinerary.Legs = append(itinerary.Legs, c.parseMainLandLeg(flightInfo))
+		}
+		itineraries = append(itineraries, itinerary)
+	}
+	return itineraries
+}
+
+// 解析国内航段数据
+func (c *CtripCrawler) parseMainLandLeg(flightInfo gjson.Result) Leg {
+	flightData := flightInfo.Get("flight")
+	leg := Leg{
+		// 航空公司和航班号
+		AirlineName:  flightData.Get("airlineName").String(),
+		FlightNumber: flightData.Get("flightNumber").String(),
+		// 起飞
+		Departure: Airport{
+			CityName: flightData.Get("departureAirportInfo").Get("cityName").String(),
+			Name:     flightData.Get("departureAirportInfo").Get("airportName").String(),
+			Terminal: flightData.Get("departureAirportInfo").Get("terminal").Get("name").String(),
+		},
+		DepartureTime: parseDateTime(flightData.Get("departureDate").String(), ChinaLocation),
+		// 到达
+		Arrival: Airport{
+			CityName: flightData.Get("arrivalAirportInfo").Get("cityName").String(),
+			Name:     flightData.Get("arrivalAirportInfo").Get("airportName").String(),
+			Terminal: flightData.Get("arrivalAirportInfo").Get("terminal").Get("name").String(),
+		},
+		ArrivalTime: parseDateTime(flightData.Get("arrivalDate").String(), ChinaLocation),
+		// 机型
+		AircraftName: flightData.Get("craftTypeName").String(),
+		AircraftCode: flightData.Get("craftTypeCode").String(),
+		// 餐食
+		HasMeal: flightData.Get("mealFlag").Bool(),
+		// 准点率
+		PunctualityRate: flightData.Get("punctualityRate").String(),
+		Fares:           make([]Fare, 0),
+	}
+	// 航班价格
+	for _, cabinInfo := range flightInfo.Get("cabins").Array() {
+		// TODO 不同价格, 暂时不清楚有啥用
+		//cabinPrice := cabinInfo.Get("price").Get("salePrice").Int()
+		//cabinPrice := cabinInfo.Get("price").Get("printPrice").Int()
+		cabinCode := cabinInfo.Get("cabinClass").String()
+		leg.Fares = append(leg.Fares, Fare{
+			Cabin:     Cabin{Code: cabinCode, Name: CabinClassMap[cabinCode]},
+			Price:     cabinInfo.Get("price").Get("price").Int(),
+			Rate:      cabinInfo.Get("price").Get("rate").Float(),
+			RestSeats: cabinInfo.Get("seatCount").Int(),
+		})
+	}
+	sort.SliceStable(leg.Fares, func(i, j int) bool {
+		return leg.Fares[i].Price < leg.Fares[j].Price
+	})
+	return leg
+}
+
+// 国内航班查询
+func (c *CtripCrawler) SearchMainLandFlights(ctx context.Context, req DomesticSearchRequest) ([]Itinerary, error) {
+	segments := []SegmentRequest{{Departure: req.Departure, Arrival: req.Arrival, Date: req.Date}}
+	return c.searchMainLandSegment(ctx, segments, req.TripType, 1)
+}
+
+// 国内多段行程查询（往返、多城市, 每段单独请求一次）
+func (c *CtripCrawler) SearchMainLandTrip(ctx context.Context, req TripSearchRequest) ([]TripSegment, error) {
+	tripSegments := make([]TripSegment, 0, len(req.Segments))
+	for i, segment := range req.Segments {
+		itineraries, err := c.searchMainLandSegment(ctx, req.Segments, req.TripType, i+1)
+		if err != nil {
+			return nil, err
+		}
+		tripSegments = append(tripSegments, TripSegment{
+			Departure:   segment.Departure,
+			Arrival:     segment.Arrival,
+			Date:        segment.Date,
+			Itineraries: itineraries,
+		})
+	}
+	return tripSegments, nil
+}
+
+// 查询行程中的某一段（searchIndex 从 1 开始）
+func (c *CtripCrawler) searchMainLandSegment(ctx context.Context, segments []SegmentRequest, tripType string, searchIndex int) ([]Itinerary, error) {
+	payloadData, err := c.getFlightTablePayload(ctx, segments, "ALL", tripType, searchIndex)
+	if err != nil {
+		return nil, err
+	}
+	dataResp, err := c.RestClient.R().
+		SetContext(ctx).
+		SetHeader("content-type", ContentTypeJson).
+		SetHeader("origin", c.client.endpoints.APIRequestOrigin).
+		SetHeader("referer", c.client.endpoints.APIRequestReferer).
+		SetHeader("user-agent", UserAgent).
+		SetBody(payloadData).
+		Post(c.client.endpoints.PlaneAPIURL)
+	tableJson, err := parseJSONResponse("国内航班", dataResp, err)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseMainLandItineraries(tableJson), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*
+1、获取 cityCode 的接口地址: https://flights.ctrip.com/international/search/api/poi/search?key=
+2、表单数据接口：https://flights.ctrip.com/international/search/oneway-{dep}-{arr}?depdate={date}&cabin=y_s&adult=1&child=0&infant=0
+3、加密参数
+加密字段在 Header 中: sign
+Js 加密参数方法源文件: https://webresource.c-ctrip.com/ResFltIntlOnline/R11/assets/list.js?v=20191031:formatted
+源代码:
+{
+	key: "genAntiCrawlerHeader",
+	value: function(e) {
+		var t = "";
+		return e.get("flightSegments").valueSeq().forEach(function(e) {
+			var n = e.get("departureCityCode")
+			  , r = e.get("arrivalCityCode")
+			  , i = e.get("departureDate");
+			t += n + r + i
+		}),
+		{
+			sign: (new b.a).update(e.get("transactionID") + t).digest("hex")
+		}
+	}
+}
+*/
+// 通过国家或者城市名查询城市号
+func (c *CtripCrawler) GetCityCode(ctx context.Context, cityName string) (string, error) {
+	params := url.Values{}
+	params.Add("key", cityName)
+	dataResp, err := c.RestClient.R().
+		SetContext(ctx).
+		SetHeader("Accept", ContentTypeJson).
+		SetHeader("user-agent", UserAgent).
+		Get(fmt.Sprintf("%s%s", c.client.endpoints.CityCodeURL, params.Encode()))
+	dataJson, err := parseJSONResponse("城市代码", dataResp, err)
+	if err != nil {
+		return "", err
+	}
+	DataArray := dataJson.Get("Data").Array()
+	if len(DataArray) == 0 || DataArray[0].Get("Code").String() == "" {
+		return "", newUnknownCityError(cityName)
+	}
+	return DataArray[0].Get("Code").String(), nil
+}
+
+// 获取 form 表单数据
+func (c *CtripCrawler) getAPIFormData(ctx context.Context, departureCityName, arriveCityName, date, cabin string) (string, error) {
+	depCode, err := c.GetCityCode(ctx, departureCityName)
+	if err != nil {
+		return "", err
+	}
+	arrCode, err := c.GetCityCode(ctx, arriveCityName)
+	if err != nil {
+		return "", err
+	}
+	reqURL := stringFormat(c.client.endpoints.FormDataURL, "{dep}", depCode, "{arr}", arrCode, "{date}", date, "{cabin}", cabin)
+	dataResp, err := c.RestClient.R().SetContext(ctx).SetHeader("User-Agent", UserAgent).Get(reqURL)
+	body, err := checkResponse("国际航班表单", dataResp, err)
+	if err != nil {
+		return "", err
+	}
+	formDataReg := regexp.MustCompile("GlobalSearchCriteria =(.*?);")
+	formData := formDataReg.FindStringSubmatch(body)
+	if len(formData) < 2 || !gjson.Valid(formData[1]) {
+		// 页面中没有查询参数时一般是跳转到了验证页面
+		return "", newAntiBotBlockedError("国际航班表单")
+	}
+	return formData[1], nil
+}
+
+// 生成加密参数 sign
+func (c *CtripCrawler) generateSignValue(data string) (string, string, error) {
+	jsonData := gjson.Parse(data)
+	transactionId := jsonData.Get("transactionID").String()
+	flightSegments := jsonData.Get("flightSegments").Array()
+	if transactionId == "" || len(flightSegments) == 0 {
+		return "", "", newParseError("国际航班表单", "缺少 transactionID 或 flightSegments")
+	}
+	depCode := flightSegments[0].Get("departureCityCode").String()
+	arrCode := flightSegments[0].Get("arrivalCityCode").String()
+	date := flightSegments[0].Get("departureDate").String()
+	return transactionId, getRandomMD5ByCustomStr(fmt.Sprintf("%s%s%s%s", transactionId, depCode, arrCode, date)), nil
+}
+
+// 解析国外航班数据
+func (c *CtripCrawler) parseOverSeaItineraries(tableJson []gjson.Result, cabin Cabin) []Itinerary {
+	itineraries := make([]Itinerary, 0)
+	for _, flightData := range tableJson {
+		// 机票航段信息
+		segments := flightData.Get("flightSegments").Array()
+		if len(segments) == 0 {
+			continue
+		}
+		flightSegments := segments[0]
+		itinerary := Itinerary{
+			Legs:     make([]Leg, 0),
+			Duration: flightSegments.Get("duration").Int(),
+			Fares:    make([]Fare, 0),
+		}
+		// 各段航班信息
+		for _, flightInfo := range flightSegments.Get("flightList").Array() {
+			itinerary.Legs = append(itinerary.Legs, Leg{
+				FlightNumber: flightInfo.Get("flightNo").String(),
+				AirlineName:  flightInfo.Get("marketAirlineName").String(),
+				AircraftName: flightInfo.Get("aircraftName").String(),
+				Departure: Airport{
+					CountryName: flightInfo.Get("departureCountryName").String(),
+					CityName:    flightInfo.Get("departureCityName").String(),
+					Name:        flightInfo.Get("departureAirportName").String(),
+					Terminal:    flightInfo.Get("departureTerminal").String(),
+				},
+				// 国际航班时间为起降地的当地时间（接口没有返回时区）
+				DepartureTime: parseDateTime(flightInfo.Get("departureDateTime").String(), airportLocalTime),
+				Arrival: Airport{
+					CountryName: flightInfo.Get("arrivalCountryName").String(),
+					CityName:    flightInfo.Get("arrivalCityName").String(),
+					Name:        flightInfo.Get("arrivalAirportName").String(),
+					Terminal:    flightInfo.Get("arrivalTerminal").String(),
+				},
+				ArrivalTime:      parseDateTime(flightInfo.Get("arrivalDateTime").String(), airportLocalTime),
+				Duration:         flightInfo.Get("duration").Int(),
+				TransferDuration: flightInfo.Get("transferDuration").Int(),
+			})
+		}
+		// 机票价格
+		for _, price := range flightData.Get("priceList").Array() {
+			itinerary.Fares = append(itinerary.Fares, Fare{
+				Cabin: cabin,
+				Price: price.Get("adultPrice").Int(),
+				Tax:   price.Get("adultTax").Int(),
+			})
+		}
+		itineraries = append(itineraries, itinerary)
+	}
+	return itineraries
+}
+
+// 国外航班舱位信息
+func (c *CtripCrawler) overSeaFlightSeatTypeToCabinName(seatType string) (string, error) {
+	cabinName := seatType
+	if seatType == "" {
+		cabinName = "y_s"
+	} else {
+		cabinName = CabinNameCode[seatType]
+	}
+	if cabinName == "" {
+		return "", NewInvalidArgumentError("舱位参数错误: %s", seatType)
+	}
+	return cabinName, nil
+}
+
+// 国外航班查询
+// 接口分多次返回结果（第一次请求后按 searchId 轮询, 直到 finished）, 各次结果按航段去重;
+// 超过 req.Timeout 仍没有加载完时返回已加载的结果
+func (c *CtripCrawler) SearchOverSeaFlights(ctx context.Context, req InternationalSearchRequest) ([]Itinerary, error) {
+	cabinName, err := c.overSeaFlightSeatTypeToCabinName(req.Cabin)
+	if err != nil {
+		return nil, err
+	}
+	pollInterval, timeout := req.PollInterval, req.Timeout
+	if pollInterval <= 0 {
+		pollInterval = DefaultInternationalPollInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultInternationalSearchTimeout
+	}
+	searchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cabin := Cabin{Code: cabinName, Name: req.Cabin}
+	itineraries := newItinerarySet()
+	emit := func(settled []Itinerary) {
+		if len(settled) > 0 && req.OnItineraries != nil {
+			req.OnItineraries(settled)
+		}
+	}
+	// 超时（而不是调用方取消）且已经加载到结果时返回已加载的结果
+	partialResult := func(err error) ([]Itinerary, error) {
+		switch {
+		case ctx.Err() != nil || searchCtx.Err() == nil:
+			return nil, err
+		case len(itineraries.itineraries) == 0:
+			return nil, newNetworkError("国际航班", fmt.Errorf("%s 内没有返回结果", timeout))
+		}
+		c.client.logger.Warnf("[Flight-Go]国际航班 %s → %s 在 %s 内没有加载完成, 返回已加载的 %d 个行程",
+			req.Departure, req.Arrival, timeout, len(itineraries.itineraries))
+		emit(itineraries.flush())
+		return itineraries.itineraries, nil
+	}
+	body, err := c.getAPIFormData(searchCtx, req.Departure, req.Arrival, req.Date, cabinName)
+	if err != nil {
+		return partialResult(err)
+	}
+	transactionId, sign, err := c.generateSignValue(body)
+	if err != nil {
+		return nil, err
+	}
+	// 获取航班数据
+	reqURL := c.client.endpoints.OverSeaAirplaneURL
+	for pulls := 0; ; pulls++ {
+		c.client.logger.Debugf("[Flight-Go]当前请求的地址: %s", reqURL)
+		dataResp, err := c.RestClient.R().
+			SetContext(searchCtx).
+			SetHeader("Content-Type", ContentTypeJson).
+			SetHeader("User-Agent", UserAgent).
+			SetHeader("sign", sign).
+			SetHeader("transactionid", transactionId).
+			SetBody(body).
+			Post(reqURL)
+		respJsonData, err := parseJSONResponse("国际航班", dataResp, err)
+		if err != nil {
+			return partialResult(err)
+		}
+		if !respJsonData.Get("data").Exists() {
+			return nil, newParseError("国际航班", "缺少 data 字段")
+		}
+		page := c.parseOverSeaItineraries(respJsonData.Get("data").Get("flightItineraryList").Array(), cabin)
+		settled := itineraries.add(page)
+		c.client.logger.Debugf("[Flight-Go]国际航班第 %d 次加载 %d 个行程, 共 %d 个行程, 其中 %d 个已确定",
+			pulls+1, len(page), len(itineraries.itineraries), len(settled))
+		emit(settled)
+		if respJsonData.Get("data").Get("context").Get("finished").Bool() {
+			// 是否加载完全部
+			break
+		}
+		searchId := respJsonData.Get("data").Get("context").Get("searchId").String()
+		if searchId == "" {
+			return nil, newParseError("国际航班", "缺少 searchId")
+		}
+		reqURL = stringFormat(c.client.endpoints.OverSeaAirplanePullDataURL, "{searchId}", searchId)
+		if err := sleepContext(searchCtx, pollInterval); err != nil {
+			return partialResult(newNetworkError("国际航班", err))
+		}
+	}
+	emit(itineraries.flush())
+	return itineraries.itineraries, nil
+}
@@ -0,0 +1,100 @@
+package flightgo
+
+// 接口地址的默认值（{ctrip}、{ctripAPIVersion}、{variflight} 会替换为 Endpoints 中对应的值）
+const (
+	DefaultCtripBaseURL      string = "https://flights.ctrip.com"
+	DefaultCtripAPIVersion   string = "12808"
+	DefaultVariFlightBaseURL string = "https://adsbapi.variflight.com"
+)
+
+// 国内航线查询的相关地址
+const (
+	PlaneAPIURL       string = "{ctrip}/itinerary/api/{ctripAPIVersion}/products"
+	APIRequestOrigin  string = "{ctrip}"
+	APIRequestReferer string = "{ctrip}/itinerary/oneway/bjs-ctu?date=2019-11-15"
+)
+
+// 国外航线查询的相关地址
+const (
+	CityCodeURL                string = "{ctrip}/international/search/api/poi/search?"
+	FormDataURL                string = "{ctrip}/international/search/oneway-{dep}-{arr}?depdate={date}&cabin={cabin}&adult=1&child=0&infant=0"
+	OverSeaAirplaneURL         string = "{ctrip}/international/search/api/search/batchSearch?v="
+	OverSeaAirplanePullDataURL string = "{ctrip}/international/search/api/search/pull/{searchId}?v="
+)
+
+// 机场和航班号信息查询的相关地址
+const (
+	AirportDepAPIURL   string = "{variflight}/adsb/airport/api/departures"
+	AirportArrAPIURL   string = "{variflight}/adsb/airport/api/arrival"
+	FlightNumberAPIURL string = "{variflight}/adsb/index/advancedSearch"
+)
+
+// 接口地址（地址中可以使用 {ctrip}、{ctripAPIVersion}、{variflight} 占位符）
+type Endpoints struct {
+	CtripBaseURL      string `json:"ctripBaseURL,omitempty"`
+	CtripAPIVersion   string `json:"ctripAPIVersion,omitempty"`
+	VariFlightBaseURL string `json:"variFlightBaseURL,omitempty"`
+
+	CityNameCodeURL            string `json:"cityNameCodeURL,omitempty"`
+	PlaneAPIURL                string `json:"planeAPIURL,omitempty"`
+	APIRequestOrigin           string `json:"apiRequestOrigin,omitempty"`
+	APIRequestReferer          string `json:"apiRequestReferer,omitempty"`
+	CityCodeURL                string `json:"cityCodeURL,omitempty"`
+	FormDataURL                string `json:"formDataURL,omitempty"`
+	OverSeaAirplaneURL         string `json:"overSeaAirplaneURL,omitempty"`
+	OverSeaAirplanePullDataURL string `json:"overSeaAirplanePullDataURL,omitempty"`
+	AirportDepAPIURL           string `json:"airportDepAPIURL,omitempty"`
+	AirportArrAPIURL           string `json:"airportArrAPIURL,omitempty"`
+	FlightNumberAPIURL         string `json:"flightNumberAPIURL,omitempty"`
+}
+
+// 默认接口地址
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		CtripBaseURL:               DefaultCtripBaseURL,
+		CtripAPIVersion:            DefaultCtripAPIVersion,
+		VariFlightBaseURL:          DefaultVariFlightBaseURL,
+		CityNameCodeURL:            CityNameCodeURL,
+		PlaneAPIURL:                PlaneAPIURL,
+		APIRequestOrigin:           APIRequestOrigin,
+		APIRequestReferer:          APIRequestReferer,
+		CityCodeURL:                CityCodeURL,
+		FormDataURL:                FormDataURL,
+		OverSeaAirplaneURL:         OverSeaAirplaneURL,
+		OverSeaAirplanePullDataURL: OverSeaAirplanePullDataURL,
+		AirportDepAPIURL:           AirportDepAPIURL,
+		AirportArrAPIURL:           AirportArrAPIURL,
+		FlightNumberAPIURL:         FlightNumberAPIURL,
+	}
+}
+
+// 全部字段（基础地址在前, 顺序固定）
+func (e *Endpoints) fields() []*string {
+	return []*string{
+		&e.CtripBaseURL, &e.CtripAPIVersion, &e.VariFlightBaseURL,
+		&e.CityNameCodeURL, &e.PlaneAPIURL, &e.APIRequestOrigin, &e.APIRequestReferer,
+		&e.CityCodeURL, &e.FormDataURL, &e.OverSeaAirplaneURL, &e.OverSeaAirplanePullDataURL,
+		&e.AirportDepAPIURL, &e.AirportArrAPIURL, &e.FlightNumberAPIURL,
+	}
+}
+
+// 补全未设置的字段并替换地址中的占位符
+func (e Endpoints) Expand() Endpoints {
+	expanded := e
+	defaults := DefaultEndpoints()
+	defaultFields := defaults.fields()
+	for i, field := range expanded.fields() {
+		if *field == "" {
+			*field = *defaultFields[i]
+		}
+	}
+	replacer := []string{
+		"{ctrip}", expanded.CtripBaseURL,
+		"{ctripAPIVersion}", expanded.CtripAPIVersion,
+		"{variflight}", expanded.VariFlightBaseURL,
+	}
+	for _, field := range expanded.fields()[3:] {
+		*field = stringFormat(*field, replacer...)
+	}
+	return expanded
+}
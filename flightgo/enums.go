@@ -0,0 +1,47 @@
+package flightgo
+
+import "fmt"
+
+// 数据源名称
+const (
+	CtripProviderName      string = "ctrip"
+	VariFlightProviderName string = "variflight"
+)
+
+// 公共常量
+const (
+	ContentTypeJson string = "application/json"
+	ContentTypeForm string = "application/x-www-form-urlencoded"
+
+	SuperEconomyClassName string = "超级经济舱"
+	EconomyClassName      string = "经济舱"
+	BusinessClassName     string = "商务舱"
+	FirstClassName        string = "头等舱"
+)
+
+var CabinClassMap = map[string]string{
+	"Y":    EconomyClassName,
+	"C":    BusinessClassName,
+	"F":    FirstClassName,
+	"S":    SuperEconomyClassName,
+	"@S-Y": fmt.Sprintf("%s-%s", SuperEconomyClassName, EconomyClassName),
+	"@S-C": fmt.Sprintf("%s-%s", SuperEconomyClassName, BusinessClassName),
+}
+
+var CabinNameCode = map[string]string{
+	"经济舱":    "y_s",
+	"超级经济舱":  "y_s",
+	"商务/头等舱": "c_f",
+	"商务舱":    "c",
+	"公务舱":    "c",
+	"头等舱":    "f",
+}
+
+// 航班动态状态码（状态名称和所处阶段见 FlightStatusCodes）
+const (
+	FlightStatusScheduled int64 = 0
+	FlightStatusDeparted  int64 = 1
+	FlightStatusArrived   int64 = 2
+	FlightStatusDelayed   int64 = 4
+	FlightStatusCancelled int64 = 73
+)
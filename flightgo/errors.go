@@ -0,0 +1,117 @@
+package flightgo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty"
+	"github.com/tidwall/gjson"
+)
+
+// 错误类型
+type ErrorKind int
+
+const (
+	// 网络请求失败（可重试）
+	ErrorNetwork ErrorKind = iota + 1
+	// 接口返回数据为空（可重试）
+	ErrorUpstreamEmpty
+	// 接口数据解析失败
+	ErrorParse
+	// 参数错误
+	ErrorInvalidArgument
+	// 未知的城市或机场
+	ErrorUnknownCity
+	// 被反爬虫拦截（稍后重试）
+	ErrorAntiBotBlocked
+)
+
+// 查询过程中的错误
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// 网络请求失败
+func newNetworkError(api string, err error) error {
+	return &Error{Kind: ErrorNetwork, Message: fmt.Sprintf("%s接口请求出错", api), Err: err}
+}
+
+// 接口返回数据为空
+func newUpstreamEmptyError(api string) error {
+	return &Error{Kind: ErrorUpstreamEmpty, Message: fmt.Sprintf("%s接口数据为空", api)}
+}
+
+// 接口数据解析失败
+func newParseError(api, reason string) error {
+	return &Error{Kind: ErrorParse, Message: fmt.Sprintf("%s接口数据解析失败, %s", api, reason)}
+}
+
+// 参数错误
+func NewInvalidArgumentError(format string, args ...interface{}) error {
+	return &Error{Kind: ErrorInvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+// 未知的城市或机场
+func newUnknownCityError(cityName string) error {
+	return &Error{Kind: ErrorUnknownCity, Message: fmt.Sprintf("未知的城市或机场: %s", cityName)}
+}
+
+// 被反爬虫拦截
+func newAntiBotBlockedError(api string) error {
+	return &Error{Kind: ErrorAntiBotBlocked, Message: fmt.Sprintf("%s接口请求被拦截, 请稍后重试", api)}
+}
+
+// 判断错误类型
+func IsErrorKind(err error, kind ErrorKind) bool {
+	var flightErr *Error
+	return errors.As(err, &flightErr) && flightErr.Kind == kind
+}
+
+// 检查接口响应（请求失败、被拦截、状态码异常和数据为空）, 返回响应内容
+func checkResponse(api string, resp *resty.Response, err error) (string, error) {
+	if err != nil {
+		return "", newNetworkError(api, err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusForbidden, http.StatusTooManyRequests, 432:
+		return "", newAntiBotBlockedError(api)
+	}
+	if resp.StatusCode() >= http.StatusBadRequest {
+		return "", newNetworkError(api, fmt.Errorf("HTTP 状态码 %d", resp.StatusCode()))
+	}
+	body := resp.String()
+	if strings.TrimSpace(body) == "" {
+		return "", newUpstreamEmptyError(api)
+	}
+	return body, nil
+}
+
+// 检查并解析 JSON 接口的响应（返回 HTML 页面时视为被拦截）
+func parseJSONResponse(api string, resp *resty.Response, err error) (gjson.Result, error) {
+	body, err := checkResponse(api, resp, err)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	if !gjson.Valid(body) {
+		if strings.HasPrefix(strings.TrimSpace(body), "<") {
+			return gjson.Result{}, newAntiBotBlockedError(api)
+		}
+		return gjson.Result{}, newParseError(api, "不是合法的 JSON")
+	}
+	return gjson.Parse(body), nil
+}
@@ -0,0 +1,236 @@
+package flightgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 时间段（一天中的分钟数, From 大于 To 时表示跨过午夜, 例如 22:00-06:00）
+type ClockWindow struct {
+	From int
+	To   int
+}
+
+// 解析时间段（格式: HH:MM-HH:MM, 例如 06:00-12:00）
+func ParseClockWindow(value string) (*ClockWindow, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return nil, NewInvalidArgumentError("时间段格式错误（需要 HH:MM-HH:MM）: %s", value)
+	}
+	minutes := make([]int, 0, 2)
+	for _, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return nil, NewInvalidArgumentError("时间段格式错误（需要 HH:MM-HH:MM）: %s", value)
+		}
+		minutes = append(minutes, t.Hour()*60+t.Minute())
+	}
+	return &ClockWindow{From: minutes[0], To: minutes[1]}, nil
+}
+
+// 时间是否在时间段内（包含两端）
+func (w ClockWindow) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.From <= w.To {
+		return minute >= w.From && minute <= w.To
+	}
+	return minute >= w.From || minute <= w.To
+}
+
+func (w ClockWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.From/60, w.From%60, w.To/60, w.To%60)
+}
+
+// 航班过滤条件（零值表示不限制）
+// 国际航班数据中没有餐食和准点率, 使用 RequireMeal 或 MinPunctuality 时国际航班都会被过滤掉
+type ItineraryFilter struct {
+	// 航空公司名称或航班号前缀（例如: 东方航空 或 MU）, 任一航段满足其中一个即可
+	Airlines []string
+	// 航班号（中转航班任一航段满足即可）
+	FlightNumber string
+	// 第一段的起飞时间和最后一段的到达时间（起降地当地时间）
+	DepartureWindow *ClockWindow
+	ArrivalWindow   *ClockWindow
+	// 机型名称或代码（例如: 320 或 波音 737）, 任一航段满足即可
+	Aircraft string
+	// 每个航段都提供餐食
+	RequireMeal bool
+	// 每个航段的最低准点率（百分比, 例如 80 表示 80%）
+	MinPunctuality float64
+	// 只保留该舱位等级的票价（例如: 经济舱）
+	CabinName string
+	// 最高含税总价（元）, 只保留不高于该价格的票价
+	MaxPrice int64
+	// 只保留直飞航班（国内航班为单个航段, 国际航班没有中转）
+	DirectOnly bool
+	// 最多中转次数（DirectOnly 为 false 时生效）
+	MaxStops int
+	// 最长转机等待时长（分钟）
+	MaxLayover int64
+}
+
+// 是否没有设置任何条件
+func (f ItineraryFilter) IsZero() bool {
+	return len(f.Airlines) == 0 && f.FlightNumber == "" && f.DepartureWindow == nil && f.ArrivalWindow == nil &&
+		f.Aircraft == "" && !f.RequireMeal && f.MinPunctuality == 0 && f.CabinName == "" && f.MaxPrice == 0 &&
+		!f.DirectOnly && f.MaxStops == 0 && f.MaxLayover == 0
+}
+
+// 航段是否满足航空公司条件
+func (f ItineraryFilter) matchAirline(leg Leg) bool {
+	for _, airline := range f.Airlines {
+		if airline == "" {
+			continue
+		}
+		if strings.Contains(leg.AirlineName, airline) || strings.HasPrefix(strings.ToUpper(leg.FlightNumber), strings.ToUpper(airline)) {
+			return true
+		}
+	}
+	return false
+}
+
+// 航段是否满足机型条件
+func (f ItineraryFilter) matchAircraft(leg Leg) bool {
+	aircraft := strings.ToUpper(strings.Replace(f.Aircraft, " ", "", -1))
+	name := strings.ToUpper(strings.Replace(leg.AircraftName, " ", "", -1))
+	return strings.Contains(name, aircraft) || strings.EqualFold(leg.AircraftCode, f.Aircraft)
+}
+
+// 准点率（例如: 92% 返回 92; 没有数据时返回 false）
+func punctualityPercent(rate string) (float64, bool) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rate), "%")), 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// 行程中最长的转机等待时长（分钟, 优先使用接口返回的时长, 没有时按前后航段的起降时间计算）
+func (it Itinerary) MaxLayover() int64 {
+	var longest int64
+	for i, leg := range it.Legs {
+		layover := leg.TransferDuration
+		if layover == 0 && i > 0 && !leg.DepartureTime.IsZero() && !it.Legs[i-1].ArrivalTime.IsZero() {
+			layover = int64(leg.DepartureTime.Sub(it.Legs[i-1].ArrivalTime.Time) / time.Minute)
+		}
+		if layover > longest {
+			longest = layover
+		}
+	}
+	return longest
+}
+
+// 中转次数
+func (it Itinerary) Stops() int {
+	if len(it.Legs) == 0 {
+		return 0
+	}
+	return len(it.Legs) - 1
+}
+
+// 行程的航段是否满足条件
+func (f ItineraryFilter) matchLegs(itinerary Itinerary) bool {
+	if len(itinerary.Legs) == 0 {
+		return false
+	}
+	airlineMatched := len(f.Airlines) == 0
+	flightNumberMatched := f.FlightNumber == ""
+	aircraftMatched := f.Aircraft == ""
+	for _, leg := range itinerary.Legs {
+		airlineMatched = airlineMatched || f.matchAirline(leg)
+		flightNumberMatched = flightNumberMatched || strings.EqualFold(leg.FlightNumber, f.FlightNumber)
+		aircraftMatched = aircraftMatched || f.matchAircraft(leg)
+		if f.RequireMeal && !leg.HasMeal {
+			return false
+		}
+		if f.MinPunctuality > 0 {
+			if rate, ok := punctualityPercent(leg.PunctualityRate); !ok || rate < f.MinPunctuality {
+				return false
+			}
+		}
+	}
+	if !airlineMatched || !flightNumberMatched || !aircraftMatched {
+		return false
+	}
+	if f.DepartureWindow != nil && !f.DepartureWindow.Contains(itinerary.Legs[0].DepartureTime.Time) {
+		return false
+	}
+	if f.ArrivalWindow != nil && !f.ArrivalWindow.Contains(itinerary.Legs[len(itinerary.Legs)-1].ArrivalTime.Time) {
+		return false
+	}
+	if f.DirectOnly && itinerary.Stops() > 0 {
+		return false
+	}
+	if f.MaxStops > 0 && itinerary.Stops() > f.MaxStops {
+		return false
+	}
+	return f.MaxLayover == 0 || itinerary.MaxLayover() <= f.MaxLayover
+}
+
+// 票价是否满足舱位和价格条件
+func (f ItineraryFilter) matchFare(fare Fare) bool {
+	if f.CabinName != "" && fare.Cabin.Name != f.CabinName {
+		return false
+	}
+	return f.MaxPrice == 0 || fare.TotalPrice() <= f.MaxPrice
+}
+
+// 过滤票价
+func (f ItineraryFilter) filterFares(fares []Fare) []Fare {
+	if len(fares) == 0 {
+		return fares
+	}
+	filtered := make([]Fare, 0, len(fares))
+	for _, fare := range fares {
+		if f.matchFare(fare) {
+			filtered = append(filtered, fare)
+		}
+	}
+	return filtered
+}
+
+// 过滤行程（设置了舱位或价格条件时, 只保留满足条件的票价, 没有满足条件的票价的行程会被过滤掉）
+func (f ItineraryFilter) Apply(itineraries []Itinerary) []Itinerary {
+	if f.IsZero() {
+		return itineraries
+	}
+	filterFares := f.CabinName != "" || f.MaxPrice > 0
+	filtered := make([]Itinerary, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		if !f.matchLegs(itinerary) {
+			continue
+		}
+		if filterFares {
+			fareCount := 0
+			legs := make([]Leg, 0, len(itinerary.Legs))
+			for _, leg := range itinerary.Legs {
+				leg.Fares = f.filterFares(leg.Fares)
+				fareCount += len(leg.Fares)
+				legs = append(legs, leg)
+			}
+			itinerary.Legs = legs
+			itinerary.Fares = f.filterFares(itinerary.Fares)
+			if fareCount+len(itinerary.Fares) == 0 {
+				continue
+			}
+		}
+		filtered = append(filtered, itinerary)
+	}
+	return filtered
+}
+
+// 过滤多段行程的每一段, 并重新计算最低价组合
+func (f ItineraryFilter) ApplyTrip(trip *Trip) *Trip {
+	if f.IsZero() {
+		return trip
+	}
+	filtered := &Trip{TripType: trip.TripType, Segments: make([]TripSegment, 0, len(trip.Segments))}
+	for _, segment := range trip.Segments {
+		segment.Itineraries = f.Apply(segment.Itineraries)
+		filtered.Segments = append(filtered.Segments, segment)
+	}
+	filtered.Cheapest = cheapestTripCombinations(filtered.Segments)
+	return filtered
+}
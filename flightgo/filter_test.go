@@ -0,0 +1,199 @@
+package flightgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClockWindow(t *testing.T) {
+	tests := []struct {
+		value  string
+		window string
+		valid  bool
+	}{
+		{"06:00-12:00", "06:00-12:00", true},
+		{" 22:00 - 06:30 ", "22:00-06:30", true},
+		{"6:00-12:00", "06:00-12:00", true},
+		{"06:00-", "", false},
+		{"06:00", "", false},
+		{"06:00-25:00", "", false},
+	}
+	for _, tt := range tests {
+		window, err := ParseClockWindow(tt.value)
+		if (err == nil) != tt.valid {
+			t.Errorf("ParseClockWindow(%q): %v, 期望合法: %v", tt.value, err, tt.valid)
+			continue
+		}
+		if tt.valid && window.String() != tt.window {
+			t.Errorf("ParseClockWindow(%q) = %s, 期望 %s", tt.value, window, tt.window)
+		}
+	}
+}
+
+func TestClockWindowContains(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2019, 11, 15, hour, min, 0, 0, ChinaLocation)
+	}
+	tests := []struct {
+		window ClockWindow
+		t      time.Time
+		want   bool
+	}{
+		{ClockWindow{From: 360, To: 720}, at(6, 0), true},
+		{ClockWindow{From: 360, To: 720}, at(12, 0), true},
+		{ClockWindow{From: 360, To: 720}, at(12, 1), false},
+		// 跨过午夜的时间段
+		{ClockWindow{From: 1320, To: 360}, at(23, 30), true},
+		{ClockWindow{From: 1320, To: 360}, at(5, 59), true},
+		{ClockWindow{From: 1320, To: 360}, at(12, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.window.Contains(tt.t); got != tt.want {
+			t.Errorf("%s 包含 %s: %v, 期望 %v", tt.window, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestPunctualityPercent(t *testing.T) {
+	tests := []struct {
+		rate  string
+		value float64
+		ok    bool
+	}{
+		{"92%", 92, true},
+		{" 85.5 % ", 85.5, true},
+		{"", 0, false},
+		{"--", 0, false},
+	}
+	for _, tt := range tests {
+		if value, ok := punctualityPercent(tt.rate); value != tt.value || ok != tt.ok {
+			t.Errorf("punctualityPercent(%q) = %v %v, 期望 %v %v", tt.rate, value, ok, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestItineraryMaxLayover(t *testing.T) {
+	at := func(hour, min int) DateTime {
+		return DateTime{Time: time.Date(2019, 11, 20, hour, min, 0, 0, ChinaLocation)}
+	}
+	tests := []struct {
+		name      string
+		itinerary Itinerary
+		stops     int
+		layover   int64
+	}{
+		{"直飞", Itinerary{Legs: []Leg{{FlightNumber: "CA925"}}}, 0, 0},
+		{"接口返回的转机时长", Itinerary{Legs: []Leg{{FlightNumber: "KE856"}, {FlightNumber: "KE703", TransferDuration: 125}}}, 1, 125},
+		{"按起降时间计算", Itinerary{Legs: []Leg{
+			{FlightNumber: "MU5101", ArrivalTime: at(10, 0)},
+			{FlightNumber: "MU5102", DepartureTime: at(11, 30), ArrivalTime: at(13, 0)},
+			{FlightNumber: "MU5103", DepartureTime: at(17, 0)},
+		}}, 2, 240},
+		{"没有航段", Itinerary{}, 0, 0},
+	}
+	for _, tt := range tests {
+		if stops, layover := tt.itinerary.Stops(), tt.itinerary.MaxLayover(); stops != tt.stops || layover != tt.layover {
+			t.Errorf("%s: 中转 %d 次, 最长转机 %d 分钟, 期望 %d 次 %d 分钟", tt.name, stops, layover, tt.stops, tt.layover)
+		}
+	}
+}
+
+func TestItineraryFilterApply(t *testing.T) {
+	at := func(hour, min int) DateTime {
+		return DateTime{Time: time.Date(2019, 11, 15, hour, min, 0, 0, ChinaLocation)}
+	}
+	itineraries := []Itinerary{
+		{Legs: []Leg{{
+			AirlineName: "中国国际航空", FlightNumber: "CA1501", AircraftName: "空客 330", AircraftCode: "333",
+			DepartureTime: at(8, 30), ArrivalTime: at(10, 40), HasMeal: true, PunctualityRate: "92%",
+			Fares: []Fare{economy(880), business(3800)},
+		}}},
+		{Legs: []Leg{{
+			AirlineName: "东方航空", FlightNumber: "MU5138", AircraftName: "波音 737", AircraftCode: "73H",
+			DepartureTime: at(7, 0), ArrivalTime: at(9, 15), PunctualityRate: "85%",
+			Fares: []Fare{economy(650)},
+		}}},
+		{
+			Legs: []Leg{
+				{AirlineName: "大韩航空", FlightNumber: "KE856", DepartureTime: at(9, 40), ArrivalTime: at(12, 50)},
+				{AirlineName: "大韩航空", FlightNumber: "KE703", DepartureTime: at(14, 55), ArrivalTime: at(17, 10), TransferDuration: 125},
+			},
+			Fares: []Fare{{Cabin: Cabin{Code: "y_s", Name: EconomyClassName}, Price: 1420, Tax: 610}},
+		},
+	}
+	window := func(value string) *ClockWindow {
+		w, err := ParseClockWindow(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+	tests := []struct {
+		name    string
+		filter  ItineraryFilter
+		flights []string
+		// 第一个结果保留的票价数量（航段票价和行程票价之和）
+		fares int
+	}{
+		{"不过滤", ItineraryFilter{}, []string{"CA1501", "MU5138", "KE856/KE703"}, 2},
+		{"航空公司名称或代码", ItineraryFilter{Airlines: []string{"东方", "KE"}}, []string{"MU5138", "KE856/KE703"}, 1},
+		{"中转航班的任一航段", ItineraryFilter{FlightNumber: "ke703"}, []string{"KE856/KE703"}, 1},
+		{"起飞时间段", ItineraryFilter{DepartureWindow: window("08:00-12:00")}, []string{"CA1501", "KE856/KE703"}, 2},
+		{"到达时间段", ItineraryFilter{ArrivalWindow: window("09:00-10:00")}, []string{"MU5138"}, 1},
+		{"机型名称", ItineraryFilter{Aircraft: "波音737"}, []string{"MU5138"}, 1},
+		{"机型代码", ItineraryFilter{Aircraft: "333"}, []string{"CA1501"}, 2},
+		{"餐食", ItineraryFilter{RequireMeal: true}, []string{"CA1501"}, 2},
+		{"准点率（国际航班没有数据）", ItineraryFilter{MinPunctuality: 85}, []string{"CA1501", "MU5138"}, 2},
+		{"舱位只保留对应票价", ItineraryFilter{CabinName: BusinessClassName}, []string{"CA1501"}, 1},
+		{"最高含税总价", ItineraryFilter{MaxPrice: 2000}, []string{"CA1501", "MU5138"}, 1},
+		{"直飞", ItineraryFilter{DirectOnly: true}, []string{"CA1501", "MU5138"}, 2},
+		{"最长转机时长", ItineraryFilter{MaxLayover: 120}, []string{"CA1501", "MU5138"}, 2},
+		{"最多中转次数", ItineraryFilter{MaxStops: 1}, []string{"CA1501", "MU5138", "KE856/KE703"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := tt.filter.Apply(itineraries)
+			flights := make([]string, 0, len(filtered))
+			for _, itinerary := range filtered {
+				flights = append(flights, itinerary.FlightNumbers())
+			}
+			if len(flights) != len(tt.flights) {
+				t.Fatalf("航班: %v, 期望 %v", flights, tt.flights)
+			}
+			for i := range flights {
+				if flights[i] != tt.flights[i] {
+					t.Fatalf("航班: %v, 期望 %v", flights, tt.flights)
+				}
+			}
+			fares := len(filtered[0].Fares)
+			for _, leg := range filtered[0].Legs {
+				fares += len(leg.Fares)
+			}
+			if fares != tt.fares {
+				t.Errorf("第一个结果的票价数量: %d, 期望 %d", fares, tt.fares)
+			}
+		})
+	}
+	// 过滤不修改原来的结果
+	if len(itineraries[0].Legs[0].Fares) != 2 {
+		t.Errorf("原结果的票价被修改: %+v", itineraries[0].Legs[0].Fares)
+	}
+}
+
+func TestItineraryFilterApplyTrip(t *testing.T) {
+	trip := &Trip{
+		TripType: TripTypeRoundTrip,
+		Segments: []TripSegment{
+			{Itineraries: []Itinerary{testItinerary("CA1501", economy(880)), testItinerary("MU5138", economy(650))}},
+			{Itineraries: []Itinerary{testItinerary("CA1502", economy(720)), testItinerary("MU5137", economy(790))}},
+		},
+	}
+	filtered := ItineraryFilter{Airlines: []string{"CA"}}.ApplyTrip(trip)
+	if len(filtered.Cheapest) != 1 || filtered.Cheapest[0].TotalPrice != 1600 {
+		t.Errorf("最低价组合: %+v, 期望 CA1501+CA1502 共 1600 元", filtered.Cheapest)
+	}
+	// 没有过滤条件时返回原行程
+	if (ItineraryFilter{}).ApplyTrip(trip) != trip {
+		t.Errorf("没有过滤条件时应返回原行程")
+	}
+}
@@ -0,0 +1,116 @@
+package flightgo
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// 国际航班默认的结果轮询间隔
+	DefaultInternationalPollInterval = time.Second
+	// 国际航班查询默认的最长时间（超过后返回已加载的结果）
+	DefaultInternationalSearchTimeout = time.Minute
+)
+
+// 行程的航段标识（各航段的航班号和起飞时间, 用于合并多次加载的结果）
+func (it Itinerary) SegmentKey() string {
+	keys := make([]string, 0, len(it.Legs))
+	for _, leg := range it.Legs {
+		keys = append(keys, leg.FlightNumber+"@"+leg.DepartureTime.String())
+	}
+	return strings.Join(keys, "/")
+}
+
+// 合并同一行程多次返回的票价（按舱位、票价、税费和折扣取并集, 按票价从低到高排列）, 返回是否有新的票价
+func mergeFares(fares, incoming []Fare) ([]Fare, bool) {
+	type fareKey struct {
+		cabin string
+		price int64
+		tax   int64
+		rate  float64
+	}
+	keyOf := func(fare Fare) fareKey {
+		return fareKey{fare.Cabin.Code, fare.Price, fare.Tax, fare.Rate}
+	}
+	seen := make(map[fareKey]bool, len(fares))
+	for _, fare := range fares {
+		seen[keyOf(fare)] = true
+	}
+	merged := append(make([]Fare, 0, len(fares)+len(incoming)), fares...)
+	for _, fare := range incoming {
+		key := keyOf(fare)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, fare)
+	}
+	if len(merged) == len(fares) {
+		return fares, false
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Price < merged[j].Price
+	})
+	return merged, true
+}
+
+// 按航段去重的行程（保持第一次出现的顺序, 再次出现时合并票价）
+//
+// 行程在之后的某一页没有新的票价时视为已确定, 边加载边输出时只输出已确定的行程;
+// 已确定的行程之后出现新的票价时仍会合并到结果中, 但不会再次输出
+type itinerarySet struct {
+	index       map[string]int
+	itineraries []Itinerary
+	// 每个行程最后一次变化的页数和是否已确定
+	changedAt []int
+	settled   []bool
+	pages     int
+}
+
+func newItinerarySet() *itinerarySet {
+	return &itinerarySet{
+		index:       make(map[string]int),
+		itineraries: make([]Itinerary, 0),
+	}
+}
+
+// 合并一页结果, 返回这一页之后确定的行程
+func (s *itinerarySet) add(page []Itinerary) []Itinerary {
+	s.pages++
+	for _, itinerary := range page {
+		key := itinerary.SegmentKey()
+		i, ok := s.index[key]
+		if !ok {
+			s.index[key] = len(s.itineraries)
+			s.itineraries = append(s.itineraries, itinerary)
+			s.changedAt = append(s.changedAt, s.pages)
+			s.settled = append(s.settled, false)
+			continue
+		}
+		if fares, changed := mergeFares(s.itineraries[i].Fares, itinerary.Fares); changed {
+			s.itineraries[i].Fares = fares
+			s.changedAt[i] = s.pages
+		}
+	}
+	settled := make([]Itinerary, 0)
+	for i, itinerary := range s.itineraries {
+		if !s.settled[i] && s.changedAt[i] < s.pages {
+			s.settled[i] = true
+			settled = append(settled, itinerary)
+		}
+	}
+	return settled
+}
+
+// 结束加载, 返回还没有确定的行程
+func (s *itinerarySet) flush() []Itinerary {
+	rest := make([]Itinerary, 0)
+	for i, itinerary := range s.itineraries {
+		if !s.settled[i] {
+			s.settled[i] = true
+			rest = append(rest, itinerary)
+		}
+	}
+	return rest
+}
@@ -0,0 +1,126 @@
+package flightgo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestItinerarySegmentKey(t *testing.T) {
+	departure := DateTime{Time: time.Date(2019, 11, 20, 9, 40, 0, 0, ChinaLocation)}
+	it := Itinerary{Legs: []Leg{
+		{FlightNumber: "KE856", DepartureTime: departure},
+		{FlightNumber: "KE703", DepartureTime: DateTime{Time: departure.Add(5*time.Hour + 15*time.Minute)}},
+	}}
+	if key := it.SegmentKey(); key != "KE856@2019-11-20T09:40:00+08:00/KE703@2019-11-20T14:55:00+08:00" {
+		t.Errorf("航段标识: %s", key)
+	}
+}
+
+// 国际航班的票价在行程上
+func testInternationalItinerary(flightNumber string, fares ...Fare) Itinerary {
+	return Itinerary{Legs: []Leg{{FlightNumber: flightNumber}}, Fares: fares}
+}
+
+func TestMergeFares(t *testing.T) {
+	fares := []Fare{economy(1850), economy(2300)}
+	merged, changed := mergeFares(fares, []Fare{economy(1850)})
+	if changed || len(merged) != 2 {
+		t.Errorf("没有新的票价: %+v, %v", merged, changed)
+	}
+	// 新的票价按从低到高合并
+	merged, changed = mergeFares(fares, []Fare{economy(1600), business(5200)})
+	if !changed || !equalPrices(farePrices(merged), []int64{1600, 1850, 2300, 5200}) {
+		t.Errorf("合并后的票价: %v, %v", farePrices(merged), changed)
+	}
+	// 票价相同但税费或折扣不同的是不同的票价
+	withTax, withRate := economy(1850), economy(1850)
+	withTax.Tax, withRate.Rate = 320, 0.8
+	merged, changed = mergeFares(fares, []Fare{withTax, withRate})
+	if !changed || len(merged) != 4 {
+		t.Errorf("税费和折扣不同的票价: %+v, %v", merged, changed)
+	}
+}
+
+func itineraryFlightNumbers(itineraries []Itinerary) string {
+	flightNumbers := make([]string, 0, len(itineraries))
+	for _, it := range itineraries {
+		flightNumbers = append(flightNumbers, it.Legs[0].FlightNumber)
+	}
+	return strings.Join(flightNumbers, " ")
+}
+
+func TestItinerarySetAdd(t *testing.T) {
+	set := newItinerarySet()
+	pages := []struct {
+		page []Itinerary
+		// 这一页之后确定的行程
+		settled string
+	}{
+		{[]Itinerary{testInternationalItinerary("CA925", economy(1850)), testInternationalItinerary("KE856", economy(1420))}, ""},
+		// KE856 有新的票价, 还没有确定
+		{[]Itinerary{testInternationalItinerary("CA925", economy(1850)), testInternationalItinerary("KE856", economy(1380))}, "CA925"},
+		// 已确定的 CA925 有新的票价时合并, 但不再输出
+		{[]Itinerary{testInternationalItinerary("NH964", economy(2100)), testInternationalItinerary("CA925", economy(1700))}, "KE856"},
+	}
+	for i, tt := range pages {
+		if settled := itineraryFlightNumbers(set.add(tt.page)); settled != tt.settled {
+			t.Errorf("第 %d 页之后确定的行程: %s, 期望 %s", i+1, settled, tt.settled)
+		}
+	}
+	if rest := itineraryFlightNumbers(set.flush()); rest != "NH964" {
+		t.Errorf("结束加载时确定的行程: %s, 期望 NH964", rest)
+	}
+	if got := itineraryFlightNumbers(set.itineraries); got != "CA925 KE856 NH964" {
+		t.Errorf("合并后的行程: %s", got)
+	}
+	if prices := farePrices(set.itineraries[0].Fares); !equalPrices(prices, []int64{1700, 1850}) {
+		t.Errorf("CA925 的票价: %v", prices)
+	}
+	if prices := farePrices(set.itineraries[1].Fares); !equalPrices(prices, []int64{1380, 1420}) {
+		t.Errorf("KE856 的票价: %v", prices)
+	}
+}
+
+// 第一次请求返回结果后一直没有加载完成的国际航班接口
+func newUnfinishedInternationalClient(t *testing.T) *Client {
+	pois := map[string]string{
+		"北京": readCassetteBody(t, "oversea/72aae9cb3ad6ef88-001.json"),
+		"东京": readCassetteBody(t, "oversea/78a8bbcd4a814358-001.json"),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/international/search/api/poi/search", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pois[r.URL.Query().Get("key")]))
+	})
+	mux.HandleFunc("/international/search/oneway-BJS-TYO", serveCassette(t, "oversea/77ec2672f04426f1-001.json"))
+	mux.HandleFunc("/international/search/api/search/batchSearch", serveCassette(t, "oversea/e03e8a661aa1a154-001.json"))
+	mux.HandleFunc("/international/search/api/search/pull/a1b2c3d4", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	return newTestClient(t, mux)
+}
+
+func TestClientSearchInternationalTimeout(t *testing.T) {
+	req := InternationalSearchRequest{Departure: "北京", Arrival: "东京", Date: "2019-11-20", PollInterval: time.Millisecond, Timeout: 100 * time.Millisecond}
+	// 超时后返回已加载的结果
+	itineraries, err := newUnfinishedInternationalClient(t).SearchInternational(context.Background(), req)
+	if err != nil || len(itineraries) != 1 || itineraries[0].Legs[0].FlightNumber != "CA925" {
+		t.Errorf("超时后的结果: %+v（%v）", itineraries, err)
+	}
+	// 调用方取消时返回错误
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req.Timeout = time.Second
+	if _, err := newUnfinishedInternationalClient(t).SearchInternational(ctx, req); !IsErrorKind(err, ErrorNetwork) {
+		t.Errorf("取消查询的错误: %v, 期望网络错误", err)
+	}
+	req.PollInterval = -time.Second
+	if _, err := newUnfinishedInternationalClient(t).SearchInternational(context.Background(), req); !IsErrorKind(err, ErrorInvalidArgument) {
+		t.Errorf("轮询间隔为负数的错误: %v, 期望参数错误", err)
+	}
+}
@@ -0,0 +1,242 @@
+package flightgo
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// 日期时间（JSON 序列化为 ISO-8601 格式, 零值序列化为 null）
+// 没有时区信息的起降地当地时间（国际航班）序列化时不带时区偏移, 例如 2019-11-20T08:20:00
+type DateTime struct {
+	time.Time
+}
+
+// 没有时区信息的当地时间格式
+const localDateTimeLayout string = "2006-01-02T15:04:05"
+
+// 起降地的当地时间（接口只返回当地时间, 不知道所在时区时使用; 只能比较同一时区的时间）
+var airportLocalTime = time.FixedZone("LOCAL", 0)
+
+// 是否为没有时区信息的当地时间
+func (d DateTime) IsLocalTime() bool {
+	return !d.IsZero() && d.Location() == airportLocalTime
+}
+
+// 序列化
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// 反序列化
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		d.Time = time.Time{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// 没有时区偏移的为当地时间
+		if t, err = time.ParseInLocation(localDateTimeLayout, value, airportLocalTime); err != nil {
+			return err
+		}
+	}
+	d.Time = t
+	return nil
+}
+
+// ISO-8601 字符串（零值时返回空字符串, 当地时间不带时区偏移）
+func (d DateTime) String() string {
+	switch {
+	case d.IsZero():
+		return ""
+	case d.IsLocalTime():
+		return d.Format(localDateTimeLayout)
+	}
+	return d.Format(time.RFC3339)
+}
+
+// 机场
+type Airport struct {
+	CountryName string `json:"countryName,omitempty"`
+	CityName    string `json:"cityName,omitempty"`
+	Name        string `json:"name,omitempty"`
+	IATA        string `json:"iata,omitempty"`
+	ICAO        string `json:"icao,omitempty"`
+	Terminal    string `json:"terminal,omitempty"`
+}
+
+// 舱位等级
+type Cabin struct {
+	// 舱位代码（国内: Y、C、F、S 等; 国际: y_s、c_f 等）
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// 票价
+type Fare struct {
+	Cabin Cabin `json:"cabin"`
+	// 票价（元, 不含税）
+	Price int64 `json:"price"`
+	// 税费（元, 仅国际航班）
+	Tax int64 `json:"tax,omitempty"`
+	// 折扣（1.0 为无折扣）
+	Rate float64 `json:"rate,omitempty"`
+	// 剩余座位数
+	RestSeats int64 `json:"restSeats,omitempty"`
+}
+
+// 含税总价
+func (f Fare) TotalPrice() int64 {
+	return f.Price + f.Tax
+}
+
+// 航段
+type Leg struct {
+	AirlineName     string   `json:"airlineName"`
+	FlightNumber    string   `json:"flightNumber"`
+	Departure       Airport  `json:"departure"`
+	DepartureTime   DateTime `json:"departureTime"`
+	Arrival         Airport  `json:"arrival"`
+	ArrivalTime     DateTime `json:"arrivalTime"`
+	AircraftName    string   `json:"aircraftName,omitempty"`
+	AircraftCode    string   `json:"aircraftCode,omitempty"`
+	HasMeal         bool     `json:"hasMeal"`
+	PunctualityRate string   `json:"punctualityRate,omitempty"`
+	// 飞行时长（分钟）
+	Duration int64 `json:"duration,omitempty"`
+	// 转机等待时长（分钟）
+	TransferDuration int64 `json:"transferDuration,omitempty"`
+	// 航段票价（国内航班按航段报价）
+	Fares []Fare `json:"fares,omitempty"`
+}
+
+// 行程（国内航班为单个航段, 国际航班可能包含多个中转航段）
+type Itinerary struct {
+	Legs []Leg `json:"legs"`
+	// 总飞行时长（分钟）
+	Duration int64 `json:"duration,omitempty"`
+	// 行程票价（国际航班按整个行程报价）
+	Fares []Fare `json:"fares,omitempty"`
+}
+
+// 航班动态
+type FlightStatus struct {
+	FlightNumber           string      `json:"flightNumber"`
+	StatusCode             int64       `json:"statusCode"`
+	Status                 string      `json:"status"`
+	Phase                  FlightPhase `json:"phase"`
+	Departure              Airport     `json:"departure"`
+	Arrival                Airport     `json:"arrival"`
+	ScheduledDepartureTime DateTime    `json:"scheduledDepartureTime"`
+	ActualDepartureTime    DateTime    `json:"actualDepartureTime"`
+	ScheduledArrivalTime   DateTime    `json:"scheduledArrivalTime"`
+	ActualArrivalTime      DateTime    `json:"actualArrivalTime"`
+	AircraftType           string      `json:"aircraftType,omitempty"`
+	AircraftNumber         string      `json:"aircraftNumber,omitempty"`
+}
+
+// 是否已到达（备降或返航后的落地不算到达）
+func (s FlightStatus) Arrived() bool {
+	if s.Diverted() {
+		return false
+	}
+	return s.Phase == FlightPhaseLanded || !s.ActualArrivalTime.IsZero()
+}
+
+// 是否已取消
+func (s FlightStatus) Cancelled() bool {
+	return s.Phase == FlightPhaseCancelled || strings.Contains(s.Status, "取消")
+}
+
+// 是否备降或返航
+func (s FlightStatus) Diverted() bool {
+	return s.Phase == FlightPhaseDiverted || s.Phase == FlightPhaseReturned
+}
+
+// 是否已经是最终状态（到达、取消, 或备降/返航后已有实际落地时间）
+func (s FlightStatus) Final() bool {
+	return s.Arrived() || s.Cancelled() || (s.Diverted() && !s.ActualArrivalTime.IsZero())
+}
+
+// 起飞延误时长（已起飞时按实际起飞时间计算, 否则为 0）
+func (s FlightStatus) DepartureDelay() time.Duration {
+	if s.ActualDepartureTime.IsZero() || s.ScheduledDepartureTime.IsZero() {
+		return 0
+	}
+	return s.ActualDepartureTime.Sub(s.ScheduledDepartureTime.Time)
+}
+
+// 到达延误时长（已到达时按实际到达时间计算, 否则为 0）
+func (s FlightStatus) ArrivalDelay() time.Duration {
+	if s.ActualArrivalTime.IsZero() || s.ScheduledArrivalTime.IsZero() {
+		return 0
+	}
+	return s.ActualArrivalTime.Sub(s.ScheduledArrivalTime.Time)
+}
+
+// 机场进出港航班
+type BoardEntry struct {
+	// 进出港类别（dep: 出港; arr: 进港）
+	Direction    string      `json:"direction"`
+	FlightNumber string      `json:"flightNumber"`
+	AircraftType string      `json:"aircraftType,omitempty"`
+	StatusCode   int64       `json:"statusCode"`
+	Status       string      `json:"status"`
+	Phase        FlightPhase `json:"phase"`
+	Departure    Airport     `json:"departure"`
+	Arrival      Airport     `json:"arrival"`
+	// 计划/实际/预计起飞（出港）或到达（进港）时间
+	ScheduledTime DateTime `json:"scheduledTime"`
+	ActualTime    DateTime `json:"actualTime"`
+	EstimatedTime DateTime `json:"estimatedTime"`
+}
+
+// 多段行程中的一段（往返行程的去程/返程, 或多城市行程中的一段）
+type TripSegment struct {
+	Departure   string      `json:"departure"`
+	Arrival     string      `json:"arrival"`
+	Date        string      `json:"date"`
+	Itineraries []Itinerary `json:"itineraries"`
+}
+
+// 多段行程中价格最低的航班组合
+type TripCombination struct {
+	// 舱位等级名称（经济舱、商务舱、头等舱）
+	CabinName string `json:"cabinName"`
+	// 每段选择的航班号（中转航班用 / 连接）
+	FlightNumbers []string `json:"flightNumbers"`
+	// 每段的票价
+	Fares []Fare `json:"fares"`
+	// 总价（元）
+	TotalPrice int64 `json:"totalPrice"`
+}
+
+// 多段行程（往返、多城市）
+type Trip struct {
+	// 行程类型（Roundtrip: 往返; Multiple: 多城市）
+	TripType string        `json:"tripType"`
+	Segments []TripSegment `json:"segments"`
+	// 各舱位等级价格最低的组合（某个舱位在任一段没有票价时不计算）
+	Cheapest []TripCombination `json:"cheapest,omitempty"`
+}
+
+// 价格日历中的一天
+type FareCalendarDay struct {
+	Date string `json:"date"`
+	// 当天的航班数量
+	Flights int `json:"flights"`
+	// 各舱位等级的最低票价（元, 0 表示没有该舱位的票价）
+	Economy  int64 `json:"economy,omitempty"`
+	Business int64 `json:"business,omitempty"`
+	First    int64 `json:"first,omitempty"`
+	// 当天查询失败时的错误信息
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,40 @@
+package flightgo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateTimeJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     DateTime
+		json      string
+		localTime bool
+	}{
+		{"零值", DateTime{}, `null`, false},
+		{"北京时间", DateTime{Time: time.Date(2019, 11, 15, 8, 30, 0, 0, ChinaLocation)}, `"2019-11-15T08:30:00+08:00"`, false},
+		// 国际航班起降地的当地时间不带时区偏移
+		{"当地时间", parseDateTime("2019-11-20 12:55:00", airportLocalTime), `"2019-11-20T12:55:00"`, true},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.value)
+		if err != nil || string(data) != tt.json {
+			t.Errorf("%s 序列化: %s（%v）, 期望 %s", tt.name, data, err, tt.json)
+			continue
+		}
+		var decoded DateTime
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Errorf("%s 反序列化失败: %v", tt.name, err)
+			continue
+		}
+		if !decoded.Equal(tt.value.Time) || decoded.IsLocalTime() != tt.localTime || decoded.String() != tt.value.String() {
+			t.Errorf("%s 反序列化: %s, 期望 %s", tt.name, decoded, tt.value)
+		}
+	}
+	var decoded DateTime
+	if err := json.Unmarshal([]byte(`"2019/11/20"`), &decoded); err == nil {
+		t.Errorf("格式错误的时间应返回错误")
+	}
+}
@@ -0,0 +1,162 @@
+package flightgo
+
+import (
+	"context"
+	"sync"
+)
+
+// 航班数据源
+type Provider interface {
+	// 数据源名称（用于 WithProvider 和命令行 -provider 参数）
+	Name() string
+}
+
+// 机票价格查询
+type FareSearcher interface {
+	Provider
+	SearchMainLandFlights(ctx context.Context, req DomesticSearchRequest) ([]Itinerary, error)
+	SearchOverSeaFlights(ctx context.Context, req InternationalSearchRequest) ([]Itinerary, error)
+}
+
+// 国内多段行程查询（往返、多城市, 按顺序返回每一段的航班）
+type TripSearcher interface {
+	Provider
+	SearchMainLandTrip(ctx context.Context, req TripSearchRequest) ([]TripSegment, error)
+}
+
+// 航班号信息查询
+type FlightStatusSearcher interface {
+	Provider
+	SearchFlightInfo(ctx context.Context, flightNumber, date string) ([]FlightStatus, error)
+}
+
+// 机场进出港信息查询
+type AirportBoardSearcher interface {
+	Provider
+	SearchAirportInfo(ctx context.Context, req AirportBoardRequest) ([]BoardEntry, error)
+}
+
+// 城市代码查询
+type CityCodeLookup interface {
+	Provider
+	GetCityCode(ctx context.Context, cityName string) (string, error)
+}
+
+// 数据源构造函数（数据源使用客户端的 HTTP 客户端、日志和接口地址）
+type ProviderFactory func(c *Client) Provider
+
+// 数据源注册表（按注册顺序保存, 未指定数据源时取第一个支持该功能的数据源）
+var (
+	providersMu       sync.RWMutex
+	providerNames     []string
+	providerFactories = make(map[string]ProviderFactory)
+)
+
+// 注册数据源（可与查询并发调用）
+func RegisterProvider(name string, factory ProviderFactory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if _, ok := providerFactories[name]; !ok {
+		providerNames = append(providerNames, name)
+	}
+	providerFactories[name] = factory
+}
+
+// 已注册的数据源名称
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return append([]string(nil), providerNames...)
+}
+
+// 数据源构造函数
+func providerFactory(name string) (ProviderFactory, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	factory, ok := providerFactories[name]
+	return factory, ok
+}
+
+// 获取数据源（每个客户端中同一数据源只构造一次, 之后的查询复用同一个实例）
+func (c *Client) providerByName(name string) (Provider, bool) {
+	factory, ok := providerFactory(name)
+	if !ok {
+		return nil, false
+	}
+	c.providersMu.Lock()
+	defer c.providersMu.Unlock()
+	if provider, ok := c.providers[name]; ok {
+		return provider, true
+	}
+	if c.providers == nil {
+		c.providers = make(map[string]Provider)
+	}
+	provider := factory(c)
+	c.providers[name] = provider
+	return provider, true
+}
+
+// 遍历可用的数据源, 直到 match 返回 true
+func (c *Client) resolveProvider(match func(Provider) bool) error {
+	if c.provider != "" {
+		provider, ok := c.providerByName(c.provider)
+		if !ok {
+			return NewInvalidArgumentError("未知的数据源: %s", c.provider)
+		}
+		if !match(provider) {
+			return NewInvalidArgumentError("数据源 %s 不支持该查询", c.provider)
+		}
+		return nil
+	}
+	for _, providerName := range Providers() {
+		if provider, ok := c.providerByName(providerName); ok && match(provider) {
+			return nil
+		}
+	}
+	return NewInvalidArgumentError("没有支持该查询的数据源")
+}
+
+// 获取机票价格查询数据源
+func (c *Client) fareSearcher() (searcher FareSearcher, err error) {
+	err = c.resolveProvider(func(provider Provider) (ok bool) {
+		searcher, ok = provider.(FareSearcher)
+		return
+	})
+	return
+}
+
+// 获取多段行程查询数据源
+func (c *Client) tripSearcher() (searcher TripSearcher, err error) {
+	err = c.resolveProvider(func(provider Provider) (ok bool) {
+		searcher, ok = provider.(TripSearcher)
+		return
+	})
+	return
+}
+
+// 获取航班号信息查询数据源
+func (c *Client) flightStatusSearcher() (searcher FlightStatusSearcher, err error) {
+	err = c.resolveProvider(func(provider Provider) (ok bool) {
+		searcher, ok = provider.(FlightStatusSearcher)
+		return
+	})
+	return
+}
+
+// 获取机场进出港信息查询数据源
+func (c *Client) airportBoardSearcher() (searcher AirportBoardSearcher, err error) {
+	err = c.resolveProvider(func(provider Provider) (ok bool) {
+		searcher, ok = provider.(AirportBoardSearcher)
+		return
+	})
+	return
+}
+
+// 获取城市代码查询数据源
+func (c *Client) cityCodeLookup() (lookup CityCodeLookup, err error) {
+	err = c.resolveProvider(func(provider Provider) (ok bool) {
+		lookup, ok = provider.(CityCodeLookup)
+		return
+	})
+	return
+}
@@ -0,0 +1,182 @@
+package flightgo
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// 排序字段
+const (
+	// 所有舱位等级中的最低价格
+	SortByPrice string = "price"
+	// 各舱位等级的最低价格
+	SortByEconomyPrice  string = "economy"
+	SortByBusinessPrice string = "business"
+	SortByFirstPrice    string = "first"
+	// 第一段的起飞时间和最后一段的到达时间
+	SortByDeparture string = "departure"
+	SortByArrival   string = "arrival"
+	// 总时长
+	SortByDuration string = "duration"
+	// 准点率（中转航班取各航段中最低的准点率）
+	SortByPunctuality string = "punctuality"
+	// 中转次数
+	SortByStops string = "stops"
+)
+
+// 排序方向
+const (
+	SortAscending  string = "asc"
+	SortDescending string = "desc"
+)
+
+var sortKeys = []string{
+	SortByPrice, SortByEconomyPrice, SortByBusinessPrice, SortByFirstPrice,
+	SortByDeparture, SortByArrival, SortByDuration, SortByPunctuality, SortByStops,
+}
+
+var sortCabinNames = map[string]string{
+	SortByEconomyPrice:  EconomyClassName,
+	SortByBusinessPrice: BusinessClassName,
+	SortByFirstPrice:    FirstClassName,
+}
+
+// 行程排序方式（Key 为空时保持原来的顺序）
+type ItinerarySort struct {
+	Key        string
+	Descending bool
+}
+
+// 解析排序方式（格式: 字段[:asc|desc], 例如 price 或 arrival:desc）
+func ParseItinerarySort(value string) (ItinerarySort, error) {
+	parts := strings.SplitN(value, ":", 2)
+	s := ItinerarySort{Key: strings.ToLower(strings.TrimSpace(parts[0]))}
+	if len(parts) == 2 {
+		switch strings.ToLower(strings.TrimSpace(parts[1])) {
+		case SortAscending:
+		case SortDescending:
+			s.Descending = true
+		default:
+			return s, NewInvalidArgumentError("排序方向错误（可选: asc, desc）: %s", parts[1])
+		}
+	}
+	for _, key := range sortKeys {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return s, NewInvalidArgumentError("不支持的排序字段: %s（可选: %s）", parts[0], strings.Join(sortKeys, ", "))
+}
+
+// 行程的排序值（没有对应数据时返回 false）
+func (s ItinerarySort) value(it Itinerary) (float64, bool) {
+	if len(it.Legs) == 0 {
+		return 0, false
+	}
+	switch s.Key {
+	case SortByPrice:
+		fare, ok := it.lowestFareOfAnyCabin()
+		return float64(fare.TotalPrice()), ok
+	case SortByEconomyPrice, SortByBusinessPrice, SortByFirstPrice:
+		fare, ok := it.LowestFare(sortCabinNames[s.Key])
+		return float64(fare.TotalPrice()), ok
+	case SortByDeparture:
+		departure := it.Legs[0].DepartureTime
+		return float64(departure.Unix()), !departure.IsZero()
+	case SortByArrival:
+		arrival := it.Legs[len(it.Legs)-1].ArrivalTime
+		return float64(arrival.Unix()), !arrival.IsZero()
+	case SortByDuration:
+		duration := it.TotalDuration()
+		return float64(duration), duration > 0
+	case SortByPunctuality:
+		lowest, found := 0.0, false
+		for _, leg := range it.Legs {
+			rate, ok := punctualityPercent(leg.PunctualityRate)
+			if !ok {
+				return 0, false
+			}
+			if !found || rate < lowest {
+				lowest, found = rate, true
+			}
+		}
+		return lowest, found
+	case SortByStops:
+		return float64(it.Stops()), true
+	}
+	return 0, false
+}
+
+// 所有舱位等级中的最低票价
+func (it Itinerary) lowestFareOfAnyCabin() (Fare, bool) {
+	fares := it.Fares
+	if len(fares) == 0 {
+		fares = nil
+		for _, leg := range it.Legs {
+			fares = append(fares, leg.Fares...)
+		}
+	}
+	var lowest Fare
+	found := false
+	for _, fare := range fares {
+		if !found || fare.TotalPrice() < lowest.TotalPrice() {
+			lowest, found = fare, true
+		}
+	}
+	return lowest, found
+}
+
+// 行程总时长（分钟, 优先使用接口返回的时长, 没有时按各航段时长或起降时间计算）
+func (it Itinerary) TotalDuration() int64 {
+	if it.Duration > 0 {
+		return it.Duration
+	}
+	var total int64
+	for _, leg := range it.Legs {
+		total += leg.Duration + leg.TransferDuration
+	}
+	if total > 0 || len(it.Legs) == 0 {
+		return total
+	}
+	departure, arrival := it.Legs[0].DepartureTime, it.Legs[len(it.Legs)-1].ArrivalTime
+	// 起降地的当地时间可能不在同一时区, 无法计算
+	if departure.IsZero() || arrival.IsZero() || departure.IsLocalTime() || arrival.IsLocalTime() {
+		return 0
+	}
+	return int64(arrival.Sub(departure.Time) / time.Minute)
+}
+
+// 排序（返回排序后的副本, 相同的值保持原来的顺序, 没有对应数据的行程排在最后）
+func (s ItinerarySort) Apply(itineraries []Itinerary) []Itinerary {
+	sorted := append([]Itinerary(nil), itineraries...)
+	if s.Key == "" {
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		vi, oki := s.value(sorted[i])
+		vj, okj := s.value(sorted[j])
+		if !oki || !okj {
+			return oki && !okj
+		}
+		if s.Descending {
+			return vi > vj
+		}
+		return vi < vj
+	})
+	return sorted
+}
+
+// 对多段行程的每一段排序
+func (s ItinerarySort) ApplyTrip(trip *Trip) *Trip {
+	if s.Key == "" {
+		return trip
+	}
+	sorted := *trip
+	sorted.Segments = make([]TripSegment, 0, len(trip.Segments))
+	for _, segment := range trip.Segments {
+		segment.Itineraries = s.Apply(segment.Itineraries)
+		sorted.Segments = append(sorted.Segments, segment)
+	}
+	return &sorted
+}
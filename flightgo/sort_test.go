@@ -0,0 +1,122 @@
+package flightgo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseItinerarySort(t *testing.T) {
+	tests := []struct {
+		value string
+		sort  ItinerarySort
+		valid bool
+	}{
+		{"price", ItinerarySort{Key: SortByPrice}, true},
+		{" Arrival : DESC ", ItinerarySort{Key: SortByArrival, Descending: true}, true},
+		{"stops:asc", ItinerarySort{Key: SortByStops}, true},
+		{"price:up", ItinerarySort{}, false},
+		{"seats", ItinerarySort{}, false},
+	}
+	for _, tt := range tests {
+		s, err := ParseItinerarySort(tt.value)
+		if (err == nil) != tt.valid {
+			t.Errorf("ParseItinerarySort(%q): %v, 期望合法: %v", tt.value, err, tt.valid)
+			continue
+		}
+		if tt.valid && s != tt.sort {
+			t.Errorf("ParseItinerarySort(%q) = %+v, 期望 %+v", tt.value, s, tt.sort)
+		}
+	}
+}
+
+func TestItineraryTotalDuration(t *testing.T) {
+	departure := DateTime{Time: time.Date(2019, 11, 15, 8, 30, 0, 0, ChinaLocation)}
+	arrival := DateTime{Time: departure.Add(130 * time.Minute)}
+	tests := []struct {
+		name      string
+		itinerary Itinerary
+		want      int64
+	}{
+		{"接口返回的时长", Itinerary{Duration: 470, Legs: []Leg{{Duration: 100}}}, 470},
+		{"各航段时长之和", Itinerary{Legs: []Leg{{Duration: 100}, {Duration: 150, TransferDuration: 60}}}, 310},
+		{"按起降时间计算", Itinerary{Legs: []Leg{{DepartureTime: departure, ArrivalTime: arrival}}}, 130},
+		{"没有时间", Itinerary{Legs: []Leg{{DepartureTime: departure}}}, 0},
+		// 起降地的当地时间可能不在同一时区
+		{"当地时间", Itinerary{Legs: []Leg{{
+			DepartureTime: parseDateTime("2019-11-20 08:20:00", airportLocalTime),
+			ArrivalTime:   parseDateTime("2019-11-20 12:55:00", airportLocalTime),
+		}}}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.itinerary.TotalDuration(); got != tt.want {
+			t.Errorf("%s: %d, 期望 %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestItinerarySortApply(t *testing.T) {
+	at := func(hour int) DateTime {
+		return DateTime{Time: time.Date(2019, 11, 15, hour, 0, 0, 0, ChinaLocation)}
+	}
+	ca1501 := testItinerary("CA1501", economy(880), business(2600))
+	ca1501.Legs[0].DepartureTime, ca1501.Legs[0].PunctualityRate = at(8), "95%"
+	mu5138 := testItinerary("MU5138", economy(650))
+	mu5138.Legs[0].DepartureTime, mu5138.Legs[0].PunctualityRate = at(7), "88%"
+	hu7605 := testItinerary("HU7605", business(1900))
+	hu7605.Legs[0].DepartureTime = at(10)
+	transfer := Itinerary{Legs: []Leg{
+		{FlightNumber: "KE856", DepartureTime: at(9), PunctualityRate: "92%"},
+		{FlightNumber: "KE703", PunctualityRate: "80%"},
+	}, Fares: []Fare{economy(1420)}}
+	itineraries := []Itinerary{ca1501, mu5138, hu7605, transfer}
+	tests := []struct {
+		sort          ItinerarySort
+		flightNumbers string
+	}{
+		{ItinerarySort{}, "CA1501 MU5138 HU7605 KE856"},
+		{ItinerarySort{Key: SortByPrice}, "MU5138 CA1501 KE856 HU7605"},
+		{ItinerarySort{Key: SortByPrice, Descending: true}, "HU7605 KE856 CA1501 MU5138"},
+		// 没有经济舱的行程排在最后
+		{ItinerarySort{Key: SortByEconomyPrice, Descending: true}, "KE856 CA1501 MU5138 HU7605"},
+		{ItinerarySort{Key: SortByBusinessPrice}, "HU7605 CA1501 MU5138 KE856"},
+		{ItinerarySort{Key: SortByDeparture}, "MU5138 CA1501 KE856 HU7605"},
+		// 中转航班取各航段中最低的准点率, 没有准点率的排在最后
+		{ItinerarySort{Key: SortByPunctuality, Descending: true}, "CA1501 MU5138 KE856 HU7605"},
+		// 相同的值保持原来的顺序
+		{ItinerarySort{Key: SortByStops, Descending: true}, "KE856 CA1501 MU5138 HU7605"},
+	}
+	for _, tt := range tests {
+		sorted := tt.sort.Apply(itineraries)
+		flightNumbers := make([]string, 0, len(sorted))
+		for _, it := range sorted {
+			flightNumbers = append(flightNumbers, it.Legs[0].FlightNumber)
+		}
+		if got := strings.Join(flightNumbers, " "); got != tt.flightNumbers {
+			t.Errorf("%+v: %s, 期望 %s", tt.sort, got, tt.flightNumbers)
+		}
+	}
+	// 不修改原来的切片
+	if itineraries[0].Legs[0].FlightNumber != "CA1501" {
+		t.Errorf("排序修改了原来的行程顺序")
+	}
+}
+
+func TestItinerarySortApplyTrip(t *testing.T) {
+	trip := &Trip{Segments: []TripSegment{
+		{Itineraries: []Itinerary{testItinerary("CA1501", economy(880)), testItinerary("MU5138", economy(650))}},
+		{Itineraries: []Itinerary{testItinerary("MU5137", economy(790)), testItinerary("CA1502", economy(720))}},
+	}}
+	if (ItinerarySort{}).ApplyTrip(trip) != trip {
+		t.Errorf("没有排序字段时应返回原来的行程")
+	}
+	sorted := ItinerarySort{Key: SortByPrice}.ApplyTrip(trip)
+	for i, want := range []string{"MU5138", "CA1502"} {
+		if got := sorted.Segments[i].Itineraries[0].Legs[0].FlightNumber; got != want {
+			t.Errorf("第 %d 段的第一个行程: %s, 期望 %s", i+1, got, want)
+		}
+	}
+	if trip.Segments[0].Itineraries[0].Legs[0].FlightNumber != "CA1501" {
+		t.Errorf("排序修改了原来的行程")
+	}
+}
@@ -0,0 +1,139 @@
+package flightgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 航班所处阶段（不同数据源的状态码统一映射到阶段）
+// 内置的状态码映射只覆盖计划、起飞、到达、延误和取消, 其他阶段需要通过 WithFlightStatusCodes 补充映射
+type FlightPhase string
+
+const (
+	FlightPhaseScheduled FlightPhase = "scheduled"
+	FlightPhaseBoarding  FlightPhase = "boarding"
+	FlightPhaseDeparted  FlightPhase = "departed"
+	FlightPhaseInAir     FlightPhase = "in-air"
+	FlightPhaseLanded    FlightPhase = "landed"
+	FlightPhaseDelayed   FlightPhase = "delayed"
+	FlightPhaseDiverted  FlightPhase = "diverted"
+	FlightPhaseReturned  FlightPhase = "returned"
+	FlightPhaseCancelled FlightPhase = "cancelled"
+	FlightPhaseUnknown   FlightPhase = "unknown"
+)
+
+// 各阶段的中文名称
+var FlightPhaseNames = map[FlightPhase]string{
+	FlightPhaseScheduled: "计划",
+	FlightPhaseBoarding:  "登机",
+	FlightPhaseDeparted:  "起飞",
+	FlightPhaseInAir:     "飞行中",
+	FlightPhaseLanded:    "到达",
+	FlightPhaseDelayed:   "延误",
+	FlightPhaseDiverted:  "备降",
+	FlightPhaseReturned:  "返航",
+	FlightPhaseCancelled: "取消",
+	FlightPhaseUnknown:   "未知",
+}
+
+// 按状态文字判断阶段时使用的关键字（按顺序匹配, 例如 "备降起飞" 为备降, "返航到达" 为返航）
+var flightPhaseKeywords = []struct {
+	keyword string
+	phase   FlightPhase
+}{
+	{"取消", FlightPhaseCancelled},
+	{"备降", FlightPhaseDiverted},
+	{"返航", FlightPhaseReturned},
+	{"延误", FlightPhaseDelayed},
+	{"登机", FlightPhaseBoarding},
+	{"值机", FlightPhaseBoarding},
+	{"飞行中", FlightPhaseInAir},
+	{"空中", FlightPhaseInAir},
+	{"起飞", FlightPhaseDeparted},
+	{"到达", FlightPhaseLanded},
+	{"落地", FlightPhaseLanded},
+	{"计划", FlightPhaseScheduled},
+}
+
+// 根据状态文字判断阶段（无法判断时返回 unknown）
+func FlightPhaseOfStatusName(name string) FlightPhase {
+	for _, k := range flightPhaseKeywords {
+		if strings.Contains(name, k.keyword) {
+			return k.phase
+		}
+	}
+	return FlightPhaseUnknown
+}
+
+// 解析阶段（支持英文标识和中文名称）
+func ParseFlightPhase(value string) (FlightPhase, error) {
+	value = strings.TrimSpace(value)
+	for phase, name := range FlightPhaseNames {
+		if strings.EqualFold(value, string(phase)) || value == name {
+			return phase, nil
+		}
+	}
+	return FlightPhaseUnknown, NewInvalidArgumentError("未知的航班阶段: %s", value)
+}
+
+// 状态码对应的状态
+type FlightStatusCode struct {
+	// 状态名称（例如: 提前取消）
+	Name string `json:"name"`
+	// 所处阶段（为空时按状态名称判断）
+	Phase FlightPhase `json:"phase,omitempty"`
+}
+
+// 飞常准（VariFlight）已确认的状态码
+// 其他状态码展示为 "未知 (状态码)", 可通过 WithFlightStatusCodes 补充
+// 登机、飞行中、备降和返航没有已确认的状态码, 需要补充映射后才会出现
+var FlightStatusCodes = map[int64]FlightStatusCode{
+	FlightStatusScheduled: {Name: "计划", Phase: FlightPhaseScheduled},
+	FlightStatusDeparted:  {Name: "起飞", Phase: FlightPhaseDeparted},
+	FlightStatusArrived:   {Name: "到达", Phase: FlightPhaseLanded},
+	FlightStatusDelayed:   {Name: "延误", Phase: FlightPhaseDelayed},
+	FlightStatusCancelled: {Name: "提前取消", Phase: FlightPhaseCancelled},
+}
+
+// 未知状态码的展示名称
+func UnknownFlightStatusName(code int64) string {
+	return fmt.Sprintf("未知 (%d)", code)
+}
+
+// 遇到的未知状态码（用于记录后补充映射）
+type UnknownFlightStatus struct {
+	ObservedAt   time.Time `json:"observedAt"`
+	Provider     string    `json:"provider"`
+	Code         int64     `json:"code"`
+	FlightNumber string    `json:"flightNumber"`
+	Departure    string    `json:"departure"`
+	Arrival      string    `json:"arrival"`
+	// 接口返回的原始数据
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// 查询状态码对应的状态（WithFlightStatusCodes 补充的映射优先于内置映射）
+// 未知状态码返回 false, 名称为 "未知 (状态码)", 阶段为 unknown
+func (c *Client) LookupFlightStatus(code int64) (FlightStatusCode, bool) {
+	status, ok := c.statusCodes[code]
+	if !ok {
+		status, ok = FlightStatusCodes[code]
+	}
+	if !ok {
+		status = FlightStatusCode{Name: UnknownFlightStatusName(code)}
+	}
+	if status.Phase == "" {
+		status.Phase = FlightPhaseOfStatusName(status.Name)
+	}
+	return status, ok
+}
+
+// 报告未知状态码（输出警告日志, 并交给 WithUnknownFlightStatusHandler 设置的处理函数）
+func (c *Client) reportUnknownFlightStatus(unknown UnknownFlightStatus) {
+	c.logger.Warnf("[Flight-Go]航班 %s（%s → %s）的状态码 %d 未知", unknown.FlightNumber, unknown.Departure, unknown.Arrival, unknown.Code)
+	if c.unknownStatusHandler != nil {
+		c.unknownStatusHandler(unknown)
+	}
+}
@@ -0,0 +1,90 @@
+package flightgo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestFlightPhaseOfStatusName(t *testing.T) {
+	tests := []struct {
+		name  string
+		phase FlightPhase
+	}{
+		{"计划", FlightPhaseScheduled},
+		{"开始登机", FlightPhaseBoarding},
+		{"起飞", FlightPhaseDeparted},
+		{"到达", FlightPhaseLanded},
+		{"提前取消", FlightPhaseCancelled},
+		// 按顺序匹配, 备降和返航优先于起飞和到达
+		{"备降起飞", FlightPhaseDiverted},
+		{"返航到达", FlightPhaseReturned},
+		{"未知 (3)", FlightPhaseUnknown},
+	}
+	for _, tt := range tests {
+		if phase := FlightPhaseOfStatusName(tt.name); phase != tt.phase {
+			t.Errorf("FlightPhaseOfStatusName(%q) = %s, 期望 %s", tt.name, phase, tt.phase)
+		}
+	}
+}
+
+func TestParseFlightPhase(t *testing.T) {
+	tests := []struct {
+		value string
+		phase FlightPhase
+		valid bool
+	}{
+		{"landed", FlightPhaseLanded, true},
+		{" In-Air ", FlightPhaseInAir, true},
+		{"备降", FlightPhaseDiverted, true},
+		{"arrived", FlightPhaseUnknown, false},
+	}
+	for _, tt := range tests {
+		phase, err := ParseFlightPhase(tt.value)
+		if (err == nil) != tt.valid || phase != tt.phase {
+			t.Errorf("ParseFlightPhase(%q) = %s, %v, 期望 %s", tt.value, phase, err, tt.phase)
+		}
+	}
+}
+
+func TestClientLookupFlightStatus(t *testing.T) {
+	client := New(WithFlightStatusCodes(map[int64]FlightStatusCode{
+		3:                     {Name: "备降"},
+		FlightStatusCancelled: {Name: "取消", Phase: FlightPhaseCancelled},
+	}))
+	tests := []struct {
+		code  int64
+		name  string
+		phase FlightPhase
+		known bool
+	}{
+		{FlightStatusArrived, "到达", FlightPhaseLanded, true},
+		// 补充的映射优先于内置映射, 没有阶段时按名称判断
+		{FlightStatusCancelled, "取消", FlightPhaseCancelled, true},
+		{3, "备降", FlightPhaseDiverted, true},
+		{99, "未知 (99)", FlightPhaseUnknown, false},
+	}
+	for _, tt := range tests {
+		status, known := client.LookupFlightStatus(tt.code)
+		if status.Name != tt.name || status.Phase != tt.phase || known != tt.known {
+			t.Errorf("LookupFlightStatus(%d) = %+v, %v, 期望 %s %s %v", tt.code, status, known, tt.name, tt.phase, tt.known)
+		}
+	}
+}
+
+func TestClientUnknownFlightStatusHandler(t *testing.T) {
+	body := readCassetteBody(t, "code/02c875756c8e35e7-001.json")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}
+	unknowns := make([]UnknownFlightStatus, 0)
+	client := newTestClient(t, http.HandlerFunc(handler), WithUnknownFlightStatusHandler(func(unknown UnknownFlightStatus) {
+		unknowns = append(unknowns, unknown)
+	}))
+	if _, err := client.FlightStatus(context.Background(), "CA1501", "20191115"); err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(unknowns) != 1 || unknowns[0].Code != 3 || unknowns[0].FlightNumber != "CA1501" || len(unknowns[0].Raw) == 0 {
+		t.Fatalf("未知状态码: %+v", unknowns)
+	}
+}
@@ -0,0 +1,185 @@
+package flightgo
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// 行程类型
+const (
+	TripTypeOneway    string = "Oneway"
+	TripTypeRoundTrip string = "Roundtrip"
+	TripTypeMultiple  string = "Multiple"
+)
+
+// 计算组合价格时使用的舱位等级
+var tripCabinNames = []string{EconomyClassName, BusinessClassName, FirstClassName}
+
+// 航段查询条件
+type SegmentRequest struct {
+	// 出发城市和到达城市（城市名, 例如: 北京）
+	Departure string
+	Arrival   string
+	// 出发日期（格式: YYYY-MM-DD）
+	Date string
+}
+
+// 国内多段行程查询请求
+type TripSearchRequest struct {
+	// 行程类型（Roundtrip 或 Multiple）
+	TripType string
+	Segments []SegmentRequest
+}
+
+// 行程中某个舱位等级的最低票价（行程票价优先, 否则为各航段最低票价之和）
+func (it Itinerary) LowestFare(cabinName string) (Fare, bool) {
+	if len(it.Fares) > 0 {
+		return lowestFare(it.Fares, cabinName)
+	}
+	var total Fare
+	for i, leg := range it.Legs {
+		fare, ok := lowestFare(leg.Fares, cabinName)
+		if !ok {
+			return Fare{}, false
+		}
+		if i == 0 {
+			total = fare
+			continue
+		}
+		total.Price += fare.Price
+		total.Tax += fare.Tax
+		total.Rate = 0
+	}
+	return total, len(it.Legs) > 0
+}
+
+// 票价中某个舱位等级的最低票价
+func lowestFare(fares []Fare, cabinName string) (Fare, bool) {
+	var lowest Fare
+	found := false
+	for _, fare := range fares {
+		if fare.Cabin.Name != cabinName {
+			continue
+		}
+		if !found || fare.TotalPrice() < lowest.TotalPrice() {
+			lowest = fare
+			found = true
+		}
+	}
+	return lowest, found
+}
+
+// 行程的航班号（中转航班用 / 连接）
+func (it Itinerary) FlightNumbers() string {
+	numbers := make([]string, 0, len(it.Legs))
+	for _, leg := range it.Legs {
+		numbers = append(numbers, leg.FlightNumber)
+	}
+	return strings.Join(numbers, "/")
+}
+
+// 计算各舱位等级价格最低的组合（每段分别取最低价即为整体最低价）
+func cheapestTripCombinations(segments []TripSegment) []TripCombination {
+	combinations := make([]TripCombination, 0)
+	for _, cabinName := range tripCabinNames {
+		combination := TripCombination{CabinName: cabinName}
+		complete := len(segments) > 0
+		for _, segment := range segments {
+			var best Itinerary
+			var bestFare Fare
+			found := false
+			for _, itinerary := range segment.Itineraries {
+				fare, ok := itinerary.LowestFare(cabinName)
+				if ok && (!found || fare.TotalPrice() < bestFare.TotalPrice()) {
+					best, bestFare, found = itinerary, fare, true
+				}
+			}
+			if !found {
+				complete = false
+				break
+			}
+			combination.FlightNumbers = append(combination.FlightNumbers, best.FlightNumbers())
+			combination.Fares = append(combination.Fares, bestFare)
+			combination.TotalPrice += bestFare.TotalPrice()
+		}
+		if complete {
+			combinations = append(combinations, combination)
+		}
+	}
+	return combinations
+}
+
+// 查询国内多段行程, 并计算最低价组合
+func (c *Client) searchTrip(ctx context.Context, req TripSearchRequest) (*Trip, error) {
+	searcher, err := c.tripSearcher()
+	if err != nil {
+		return nil, err
+	}
+	segments, err := searcher.SearchMainLandTrip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &Trip{
+		TripType: req.TripType,
+		Segments: segments,
+		Cheapest: cheapestTripCombinations(segments),
+	}, nil
+}
+
+// 查询国内往返航班（需要设置 ReturnDate）, 返回去程、返程和最低往返价格
+func (c *Client) SearchRoundTrip(ctx context.Context, req DomesticSearchRequest) (*Trip, error) {
+	if req.ReturnDate == "" {
+		return nil, NewInvalidArgumentError("往返查询需要返程日期")
+	}
+	departDate, err := time.Parse(dateLayout, req.Date)
+	if err != nil {
+		return nil, NewInvalidArgumentError("去程日期格式错误: %s（格式: YYYY-MM-DD）", req.Date)
+	}
+	returnDate, err := time.Parse(dateLayout, req.ReturnDate)
+	if err != nil {
+		return nil, NewInvalidArgumentError("返程日期格式错误: %s（格式: YYYY-MM-DD）", req.ReturnDate)
+	}
+	if returnDate.Before(departDate) {
+		return nil, NewInvalidArgumentError("返程日期 %s 早于去程日期 %s", req.ReturnDate, req.Date)
+	}
+	return c.searchTrip(ctx, TripSearchRequest{
+		TripType: TripTypeRoundTrip,
+		Segments: []SegmentRequest{
+			{Departure: req.Departure, Arrival: req.Arrival, Date: req.Date},
+			{Departure: req.Arrival, Arrival: req.Departure, Date: req.ReturnDate},
+		},
+	})
+}
+
+// 查询国内往返航班（使用临时客户端）
+func SearchRoundTrip(ctx context.Context, req DomesticSearchRequest, opts ...Option) (*Trip, error) {
+	return New(opts...).SearchRoundTrip(ctx, req)
+}
+
+// 查询国内多城市行程（按顺序的多段航班, 例如 A→B 第 1 天、C→D 第 4 天）, 返回每段的航班和最低组合价格
+func (c *Client) SearchMultiCity(ctx context.Context, segments []SegmentRequest) (*Trip, error) {
+	if len(segments) < 2 {
+		return nil, NewInvalidArgumentError("多城市行程至少需要 2 段, 实际 %d 段", len(segments))
+	}
+	var previousDate time.Time
+	for i, segment := range segments {
+		if segment.Departure == "" || segment.Arrival == "" || segment.Date == "" {
+			return nil, NewInvalidArgumentError("第 %d 段缺少出发地、到达地或日期", i+1)
+		}
+		date, err := time.Parse(dateLayout, segment.Date)
+		if err != nil {
+			return nil, NewInvalidArgumentError("第 %d 段的日期格式错误: %s（格式: YYYY-MM-DD）", i+1, segment.Date)
+		}
+		if i > 0 && date.Before(previousDate) {
+			return nil, NewInvalidArgumentError("第 %d 段的日期 %s 早于上一段的日期 %s", i+1, segment.Date, segments[i-1].Date)
+		}
+		previousDate = date
+	}
+	return c.searchTrip(ctx, TripSearchRequest{TripType: TripTypeMultiple, Segments: segments})
+}
+
+// 查询国内多城市行程（使用临时客户端）
+func SearchMultiCity(ctx context.Context, segments []SegmentRequest, opts ...Option) (*Trip, error) {
+	return New(opts...).SearchMultiCity(ctx, segments)
+}
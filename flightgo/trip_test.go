@@ -0,0 +1,124 @@
+package flightgo
+
+import (
+	"context"
+	"testing"
+)
+
+// 单航段行程
+func testItinerary(flightNumber string, fares ...Fare) Itinerary {
+	return Itinerary{Legs: []Leg{{FlightNumber: flightNumber, Fares: fares}}}
+}
+
+func economy(price int64) Fare {
+	return Fare{Cabin: Cabin{Code: "Y", Name: EconomyClassName}, Price: price}
+}
+
+func business(price int64) Fare {
+	return Fare{Cabin: Cabin{Code: "C", Name: BusinessClassName}, Price: price}
+}
+
+func TestItineraryLowestFare(t *testing.T) {
+	transfer := Itinerary{Legs: []Leg{
+		{FlightNumber: "MU5101", Fares: []Fare{economy(500), economy(450)}},
+		{FlightNumber: "MU5102", Fares: []Fare{economy(300)}},
+	}}
+	tests := []struct {
+		name      string
+		itinerary Itinerary
+		cabinName string
+		price     int64
+		ok        bool
+	}{
+		{"航段最低价", testItinerary("CA1501", economy(900), economy(880), business(2000)), EconomyClassName, 880, true},
+		{"各航段最低价之和", transfer, EconomyClassName, 750, true},
+		{"某个航段没有该舱位", transfer, BusinessClassName, 0, false},
+		{"行程票价优先", Itinerary{Legs: transfer.Legs, Fares: []Fare{economy(700)}}, EconomyClassName, 700, true},
+		{"没有航段", Itinerary{}, EconomyClassName, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fare, ok := tt.itinerary.LowestFare(tt.cabinName)
+			if ok != tt.ok || fare.Price != tt.price {
+				t.Errorf("最低价: %d（%v）, 期望 %d（%v）", fare.Price, ok, tt.price, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCheapestTripCombinations(t *testing.T) {
+	segments := []TripSegment{
+		{Itineraries: []Itinerary{
+			testItinerary("CA1501", economy(880), business(2600)),
+			testItinerary("MU5138", economy(650)),
+		}},
+		{Itineraries: []Itinerary{
+			testItinerary("CA1502", economy(720), business(2400)),
+			testItinerary("MU5137", economy(790)),
+		}},
+	}
+	combinations := cheapestTripCombinations(segments)
+	tests := []struct {
+		cabinName     string
+		flightNumbers []string
+		totalPrice    int64
+	}{
+		{EconomyClassName, []string{"MU5138", "CA1502"}, 1370},
+		{BusinessClassName, []string{"CA1501", "CA1502"}, 5000},
+	}
+	// 头等舱在各段都没有票价, 不计算组合
+	if len(combinations) != len(tests) {
+		t.Fatalf("组合数量: %d, 期望 %d（%+v）", len(combinations), len(tests), combinations)
+	}
+	for i, tt := range tests {
+		combination := combinations[i]
+		if combination.CabinName != tt.cabinName || combination.TotalPrice != tt.totalPrice ||
+			len(combination.FlightNumbers) != 2 || combination.FlightNumbers[0] != tt.flightNumbers[0] || combination.FlightNumbers[1] != tt.flightNumbers[1] {
+			t.Errorf("组合: %+v, 期望 %s %v %d", combination, tt.cabinName, tt.flightNumbers, tt.totalPrice)
+		}
+	}
+}
+
+func TestSearchRoundTripArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		req  DomesticSearchRequest
+	}{
+		{"缺少返程日期", DomesticSearchRequest{Departure: "北京", Arrival: "上海", Date: "2019-11-15"}},
+		{"返程早于去程", DomesticSearchRequest{Departure: "北京", Arrival: "上海", Date: "2019-11-15", ReturnDate: "2019-11-14"}},
+		// 按日期比较, 不按字符串比较
+		{"返程日期格式错误", DomesticSearchRequest{Departure: "北京", Arrival: "上海", Date: "2019-11-15", ReturnDate: "2019-11-9"}},
+		{"去程日期格式错误", DomesticSearchRequest{Departure: "北京", Arrival: "上海", Date: "20191115", ReturnDate: "2019-11-16"}},
+	}
+	for _, tt := range tests {
+		if _, err := New().SearchRoundTrip(context.Background(), tt.req); !IsErrorKind(err, ErrorInvalidArgument) {
+			t.Errorf("%s: %v, 期望参数错误", tt.name, err)
+		}
+	}
+}
+
+func TestSearchMultiCityArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []SegmentRequest
+	}{
+		{"只有 1 段", []SegmentRequest{{Departure: "北京", Arrival: "上海", Date: "2019-11-15"}}},
+		{"缺少日期", []SegmentRequest{
+			{Departure: "北京", Arrival: "上海", Date: "2019-11-15"},
+			{Departure: "上海", Arrival: "广州"},
+		}},
+		{"日期早于上一段", []SegmentRequest{
+			{Departure: "北京", Arrival: "上海", Date: "2019-11-15"},
+			{Departure: "上海", Arrival: "广州", Date: "2019-11-14"},
+		}},
+		{"日期格式错误", []SegmentRequest{
+			{Departure: "北京", Arrival: "上海", Date: "2019-11-15"},
+			{Departure: "上海", Arrival: "广州", Date: "2019/11/16"},
+		}},
+	}
+	for _, tt := range tests {
+		if _, err := New().SearchMultiCity(context.Background(), tt.segments); !IsErrorKind(err, ErrorInvalidArgument) {
+			t.Errorf("%s: %v, 期望参数错误", tt.name, err)
+		}
+	}
+}
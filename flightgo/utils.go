@@ -0,0 +1,57 @@
+package flightgo
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+const (
+	UserAgent string = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.70 Safari/537.36"
+)
+
+// 北京时间（国内航班接口返回的时间均为北京时间, 日期和时间段也按北京时间计算）
+var ChinaLocation = time.FixedZone("CST", 8*60*60)
+
+// 时间戳转时间（北京时间; 时间戳为 0 时返回零值）
+func unixToTime(timestamp int64) DateTime {
+	if timestamp != 0 {
+		return DateTime{time.Unix(timestamp, 0).In(ChinaLocation)}
+	}
+	return DateTime{}
+}
+
+// 解析接口返回的日期时间字符串（格式: 2006-01-02 15:04:05）
+func parseDateTime(value string, loc *time.Location) DateTime {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, loc)
+	if err != nil {
+		return DateTime{}
+	}
+	return DateTime{t}
+}
+
+// 字符串 format
+func stringFormat(format string, args ...string) (formatString string) {
+	return strings.NewReplacer(args...).Replace(format)
+}
+
+// 通过自定义字符串获取随机 MD5 字符串
+func getRandomMD5ByCustomStr(str string) string {
+	md5Context := md5.New()
+	md5Context.Write([]byte(str))
+	return hex.EncodeToString(md5Context.Sum(nil))
+}
+
+// 等待一段时间（context 取消时提前返回错误）
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
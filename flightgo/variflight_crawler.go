@@ -0,0 +1,209 @@
+package flightgo
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty"
+	"github.com/tidwall/gjson"
+)
+
+type VariFlightCrawler struct {
+	RestClient *resty.Client
+	client     *Client
+}
+
+func NewVariFlightCrawler(client *Client) *VariFlightCrawler {
+	vari := &VariFlightCrawler{client: client}
+	vari.initVariFlightCrawler()
+	return vari
+}
+
+func init() {
+	RegisterProvider(VariFlightProviderName, func(c *Client) Provider {
+		return NewVariFlightCrawler(c)
+	})
+}
+
+// 数据源名称
+func (v *VariFlightCrawler) Name() string {
+	return VariFlightProviderName
+}
+
+// 初始化
+func (v *VariFlightCrawler) initVariFlightCrawler() {
+	v.RestClient = v.client.newRestClient()
+}
+
+// 构造航班信息请求数据
+func (v *VariFlightCrawler) getFlightNumberPayload(flightNumber, date string) (payload map[string]string) {
+	payload = make(map[string]string)
+	payload["searchText"] = flightNumber
+	payload["searchDate"] = date
+	payload["timeZone"] = "-28800"
+	return
+}
+
+// 状态码对应的状态（未知的状态码连同原始数据一起报告）
+func (v *VariFlightCrawler) lookupStatus(data gjson.Result, flightNumber, departure, arrival string) (int64, FlightStatusCode) {
+	statusCode := data.Get("flightStatusCode").Int()
+	status, ok := v.client.LookupFlightStatus(statusCode)
+	if !ok {
+		v.client.reportUnknownFlightStatus(UnknownFlightStatus{
+			ObservedAt:   time.Now(),
+			Provider:     v.Name(),
+			Code:         statusCode,
+			FlightNumber: flightNumber,
+			Departure:    departure,
+			Arrival:      arrival,
+			Raw:          json.RawMessage(data.Raw),
+		})
+	}
+	return statusCode, status
+}
+
+// 解析航班信息
+func (v *VariFlightCrawler) parseFlightStatuses(tableJson gjson.Result) []FlightStatus {
+	statuses := make([]FlightStatus, 0)
+	for _, data := range tableJson.Get("data").Array() {
+		flightNumber := data.Get("fnum").String()
+		departure, arrival := data.Get("forgAptCname").String(), data.Get("fdstAptCname").String()
+		statusCode, status := v.lookupStatus(data, flightNumber, departure, arrival)
+		statuses = append(statuses, FlightStatus{
+			FlightNumber:           flightNumber,
+			StatusCode:             statusCode,
+			Status:                 status.Name,
+			Phase:                  status.Phase,
+			Departure:              Airport{Name: departure},
+			Arrival:                Airport{Name: arrival},
+			ScheduledDepartureTime: unixToTime(data.Get("scheduledDeptime").Int()),
+			ActualDepartureTime:    unixToTime(data.Get("actualDeptime").Int()),
+			ScheduledArrivalTime:   unixToTime(data.Get("scheduledArrtime").Int()),
+			ActualArrivalTime:      unixToTime(data.Get("actualArrtime").Int()),
+			AircraftType:           data.Get("ftype").String(),
+			AircraftNumber:         data.Get("aircraftNumber").String(),
+		})
+	}
+	return statuses
+}
+
+// 查询航班信息
+func (v *VariFlightCrawler) SearchFlightInfo(ctx context.Context, flightNumber, date string) ([]FlightStatus, error) {
+	payloadData := v.getFlightNumberPayload(flightNumber, date)
+	dataResp, err := v.RestClient.R().
+		SetContext(ctx).
+		SetQueryParam("lang", "zh_CN").
+		SetHeader("Content-Type", ContentTypeForm).
+		SetHeader("User-Agent", UserAgent).
+		SetFormData(payloadData).
+		Post(v.client.endpoints.FlightNumberAPIURL)
+	tableJson, err := parseJSONResponse("航班号", dataResp, err)
+	if err != nil {
+		return nil, err
+	}
+	return v.parseFlightStatuses(tableJson), nil
+}
+
+// 解析机场进出港数据
+func (v *VariFlightCrawler) parseBoardEntries(depOrArr string, tableJson gjson.Result) []BoardEntry {
+	entries := make([]BoardEntry, 0)
+	for _, airportInfo := range tableJson.Get("list").Array() {
+		flightNumber := airportInfo.Get("fnum").String()
+		departure := Airport{
+			CityName: airportInfo.Get("forgAptCcity").String(),
+			Name:     airportInfo.Get("forgAptCname").String(),
+		}
+		arrival := Airport{
+			CityName: airportInfo.Get("fdstAptCcity").String(),
+			Name:     airportInfo.Get("fdstAptCname").String(),
+		}
+		statusCode, status := v.lookupStatus(airportInfo, flightNumber, departure.Name, arrival.Name)
+		entry := BoardEntry{
+			Direction:    depOrArr,
+			FlightNumber: flightNumber,
+			AircraftType: airportInfo.Get("ftype").String(),
+			StatusCode:   statusCode,
+			Status:       status.Name,
+			Phase:        status.Phase,
+			Departure:    departure,
+			Arrival:      arrival,
+		}
+		switch depOrArr {
+		case DirectionDeparture:
+			entry.ScheduledTime = unixToTime(airportInfo.Get("scheduledDeptime").Int())
+			entry.ActualTime = unixToTime(airportInfo.Get("actualDeptime").Int())
+			entry.EstimatedTime = unixToTime(airportInfo.Get("estimatedDeptime").Int())
+		case DirectionArrival:
+			entry.ScheduledTime = unixToTime(airportInfo.Get("scheduledArrtime").Int())
+			entry.ActualTime = unixToTime(airportInfo.Get("actualArrtime").Int())
+			entry.EstimatedTime = unixToTime(airportInfo.Get("estimatedArrtime").Int())
+		default:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// 查询机场进出港信息的一页（接口没有日期参数, 按日期过滤在取完全部页之后进行）
+func (v *VariFlightCrawler) getAirportBoardPage(ctx context.Context, reqURL, cityCode string, pageNum, pageSize int) (gjson.Result, error) {
+	dataResp, err := v.RestClient.R().
+		SetContext(ctx).
+		SetQueryParam("lang", "zh_CN").
+		SetQueryParam("iata", cityCode).
+		SetQueryParam("pageSize", strconv.Itoa(pageSize)).
+		SetQueryParam("pageNum", strconv.Itoa(pageNum)).
+		SetHeader("User-Agent", UserAgent).
+		Get(reqURL)
+	return parseJSONResponse("机场进出港", dataResp, err)
+}
+
+// 查询机场进出港信息（自动翻页, 直到取完接口返回的总数或某一页不满）
+func (v *VariFlightCrawler) SearchAirportInfo(ctx context.Context, req AirportBoardRequest) ([]BoardEntry, error) {
+	var ReqURL string
+	switch req.Direction {
+	case DirectionDeparture:
+		ReqURL = v.client.endpoints.AirportDepAPIURL
+	case DirectionArrival:
+		ReqURL = v.client.endpoints.AirportArrAPIURL
+	default:
+		return nil, NewInvalidArgumentError("进出港字段错误: %s（进港: arr; 出港: dep）", req.Direction)
+	}
+	cityCode := req.AirportCode
+	if cityCode == "" {
+		cityCode = v.client.lookupCityCode(req.Airport)
+	}
+	if cityCode == "" {
+		return nil, newUnknownCityError(req.Airport)
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultAirportBoardPageSize
+	}
+	entries := make([]BoardEntry, 0)
+	seen := make(map[string]bool)
+	fetched := 0
+	for pageNum := 1; pageNum <= airportBoardMaxPages; pageNum++ {
+		tableJson, err := v.getAirportBoardPage(ctx, ReqURL, cityCode, pageNum, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		// 翻页过程中有新航班加入时, 前一页的航班可能出现在下一页
+		for _, entry := range v.parseBoardEntries(req.Direction, tableJson) {
+			if !seen[entry.Key()] {
+				seen[entry.Key()] = true
+				entries = append(entries, entry)
+			}
+		}
+		count := len(tableJson.Get("list").Array())
+		fetched += count
+		total := tableJson.Get("total").Int()
+		v.client.logger.Debugf("[Flight-Go]机场进出港第 %d 页: %d 条, 已获取 %d/%d 条", pageNum, count, fetched, total)
+		if count < pageSize || (total > 0 && int64(fetched) >= total) {
+			break
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,80 @@
+package flightgo
+
+import (
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestParseFlightStatuses(t *testing.T) {
+	body := readCassetteBody(t, "code/02c875756c8e35e7-001.json")
+	statuses := NewVariFlightCrawler(New()).parseFlightStatuses(gjson.Parse(body))
+	tests := []struct {
+		statusCode      int64
+		status          string
+		phase           FlightPhase
+		scheduled       string
+		actualDeparture string
+		actualArrival   string
+		aircraftNumber  string
+	}{
+		{2, "到达", FlightPhaseLanded, "2019-11-15T08:30:00+08:00", "2019-11-15T08:45:00+08:00", "2019-11-15T10:45:00+08:00", "B-2472"},
+		// 未开始的航班没有实际起降时间, 未知状态码展示为 "未知 (状态码)"
+		{3, "未知 (3)", FlightPhaseUnknown, "2019-11-16T08:30:00+08:00", "", "", "B-2479"},
+	}
+	if len(statuses) != len(tests) {
+		t.Fatalf("航班数量: %d, 期望 %d", len(statuses), len(tests))
+	}
+	for i, tt := range tests {
+		status := statuses[i]
+		if status.FlightNumber != "CA1501" || status.StatusCode != tt.statusCode || status.Status != tt.status || status.Phase != tt.phase {
+			t.Errorf("第 %d 条: %s %d %s %s, 期望 CA1501 %d %s %s", i+1, status.FlightNumber, status.StatusCode, status.Status, status.Phase, tt.statusCode, tt.status, tt.phase)
+		}
+		if !status.ScheduledDepartureTime.Equal(parseTestTime(t, tt.scheduled)) ||
+			status.ActualDepartureTime.IsZero() != (tt.actualDeparture == "") ||
+			status.ActualArrivalTime.IsZero() != (tt.actualArrival == "") {
+			t.Errorf("第 %d 条起降时间: %s %s %s", i+1, status.ScheduledDepartureTime, status.ActualDepartureTime, status.ActualArrivalTime)
+		}
+		if tt.actualArrival != "" && !status.ActualArrivalTime.Equal(parseTestTime(t, tt.actualArrival)) {
+			t.Errorf("第 %d 条实际到达时间: %s, 期望 %s", i+1, status.ActualArrivalTime, tt.actualArrival)
+		}
+		if status.Departure.Name != "北京首都" || status.Arrival.Name != "上海虹桥" || status.AircraftNumber != tt.aircraftNumber {
+			t.Errorf("第 %d 条: %+v", i+1, status)
+		}
+	}
+}
+
+func TestParseBoardEntries(t *testing.T) {
+	tests := []struct {
+		direction string
+		cassette  string
+		// 航班号、状态和阶段
+		entries [][3]string
+		// 第一条的计划时间（出港为起飞时间, 进港为到达时间）
+		scheduled string
+	}{
+		{"dep", "airport-dep/ca5cb1bb9de38622-001.json", [][3]string{{"CZ3101", "起飞", "departed"}, {"CZ3539", "延误", "delayed"}, {"HU7808", "提前取消", "cancelled"}}, "2019-11-15T08:00:00+08:00"},
+		{"arr", "airport-arr/48466dae8b50418e-001.json", [][3]string{{"CA1315", "到达", "landed"}, {"MU5301", "计划", "scheduled"}}, "2019-11-15T10:00:00+08:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			entries := NewVariFlightCrawler(New()).parseBoardEntries(tt.direction, gjson.Parse(readCassetteBody(t, tt.cassette)))
+			if len(entries) != len(tt.entries) {
+				t.Fatalf("航班数量: %d, 期望 %d", len(entries), len(tt.entries))
+			}
+			for i, want := range tt.entries {
+				if entries[i].Direction != tt.direction || entries[i].FlightNumber != want[0] || entries[i].Status != want[1] || string(entries[i].Phase) != want[2] {
+					t.Errorf("第 %d 条: %s %s %s %s, 期望 %s %s %s %s", i+1, entries[i].Direction, entries[i].FlightNumber, entries[i].Status, entries[i].Phase, tt.direction, want[0], want[1], want[2])
+				}
+			}
+			if !entries[0].ScheduledTime.Equal(parseTestTime(t, tt.scheduled)) {
+				t.Errorf("计划时间: %s, 期望 %s", entries[0].ScheduledTime, tt.scheduled)
+			}
+		})
+	}
+	// 未知的进出港类别不返回数据
+	body := readCassetteBody(t, "airport-dep/ca5cb1bb9de38622-001.json")
+	if entries := NewVariFlightCrawler(New()).parseBoardEntries("all", gjson.Parse(body)); len(entries) != 0 {
+		t.Errorf("未知类别返回了 %d 条数据", len(entries))
+	}
+}
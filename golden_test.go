@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+var updateGolden = flag.Bool("update", false, "用当前输出覆盖 testdata/golden 中的预期输出")
+
+// 设置该环境变量时测试程序作为 flight_go 命令运行, 供回归用例回放录制的接口数据
+const goldenMainEnv = "FLIGHT_GO_GOLDEN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(goldenMainEnv) == "1" {
+		os.Args = append([]string{"flight_go"}, os.Args[1:]...)
+		main()
+		os.Exit(0)
+	}
+	// 测试中的日志不写入文件, 用户目录指向临时目录, 不读写本机的配置和城市缓存
+	quiet := logrus.New()
+	quiet.SetOutput(ioutil.Discard)
+	logger = logrus.NewEntry(quiet)
+	home, err := ioutil.TempDir("", "flight-go-test")
+	if err != nil {
+		panic(err)
+	}
+	for _, env := range []string{"HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME"} {
+		_ = os.Setenv(env, home)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// 回归用例: 使用 testdata/cassettes 中录制的接口数据回放命令, 并与 testdata/golden 中的预期输出对比
+// 参数中的 {cassettes}、{testdata} 替换为对应目录, {work} 替换为命令运行的临时目录
+var goldenCases = []struct {
+	name string
+	args string
+}{
+	{"schedule", "schedule -replay {cassettes}/schedule -output json 北京 上海 2019-11-15"},
+	{"oversea", "oversea -replay {cassettes}/oversea -output json 北京 东京 2019-11-20 经济舱"},
+	{"oversea-partial", "oversea -replay {cassettes}/oversea-partial -interval 200ms -timeout 1s -output ndjson 北京 东京 2019-11-20 经济舱"},
+	{"oversea-stream", "oversea -replay {cassettes}/oversea -interval 200ms -output ndjson 北京 东京 2019-11-20 经济舱"},
+	{"code", "code -replay {cassettes}/code -output json CA1501 20191115"},
+	{"airport-dep", "airport -replay {cassettes}/airport-dep -output json 广州 dep"},
+	{"airport-arr", "airport -replay {cassettes}/airport-arr -output json 广州 arr"},
+	{"airport-paged", "airport -replay {cassettes}/airport-dep-paged -date 2019-11-15 -window 08:00-12:00 广州 dep"},
+	{"airport-city", "airport -replay {cassettes}/airport-dep-city 北京 dep"},
+	{"airport-code", "airport -replay {cassettes}/airport-dep-city -output csv ZBAD dep"},
+	{"airport-filter", "airport -replay {cassettes}/airport-dep-paged -output csv -date 2019-11-15 -status delayed,取消 -airline HU,3U 广州 dep"},
+	{"airport-stats", "airport -offline -since 2019-11-14 -until 2019-11-14 stats 广州 dep"},
+	{"airport-stats-live", "airport -replay {cassettes}/airport-dep-paged -date 2019-11-15 -output csv -top 3 stats 广州 dep"},
+	{"schedule-table", "schedule -replay {cassettes}/schedule 北京 上海 2019-11-15"},
+	{"schedule-filter", "schedule -replay {cassettes}/schedule -depart 08:00-12:00 -meal -min-punctuality 90 北京 上海 2019-11-15"},
+	{"oversea-filter", "oversea -replay {cassettes}/oversea -output json -max-stops 0 -max-price 3000 北京 东京 2019-11-20 经济舱"},
+	{"schedule-sort", "schedule -replay {cassettes}/schedule -sort departure 北京 上海 2019-11-15"},
+	{"oversea-sort", "oversea -replay {cassettes}/oversea -output csv -sort stops:desc 北京 东京 2019-11-20 经济舱"},
+	{"schedule-all-fares", "schedule -replay {cassettes}/schedule -all-fares 北京 上海 2019-11-15"},
+	{"roundtrip", "schedule -replay {cassettes}/roundtrip -output json 北京 上海 2019-11-15 2019-11-18"},
+	{"roundtrip-table", "schedule -replay {cassettes}/roundtrip 北京 上海 2019-11-15 2019-11-18"},
+	{"multicity-table", "schedule -replay {cassettes}/multicity -multi 北京 上海 2019-11-15 上海 广州 2019-11-18 广州 北京 2019-11-20"},
+	{"calendar", "schedule -replay {cassettes}/calendar -output csv -flex 2 北京 上海 2019-11-15"},
+	{"calendar-table", "schedule -replay {cassettes}/calendar -until 2019-11-17 北京 上海 2019-11-13"},
+	{"watch", "watch -replay {cassettes}/watch -once -state {work}/watch_state.json {testdata}/watch/config.json"},
+	{"code-csv", "code -replay {cassettes}/code -output csv CA1501 20191115"},
+	{"history-table", "history -flight CA1501 -cabin 经济舱 北京 上海 2019-11-15"},
+	{"history-summary", "history -summary -output csv 北京 上海"},
+}
+
+func TestGolden(t *testing.T) {
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range goldenCases {
+		t.Run(tt.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			args := strings.Fields(strings.NewReplacer(
+				"{cassettes}", filepath.Join(testdata, "cassettes"),
+				"{testdata}", testdata,
+				"{work}", workDir,
+			).Replace(tt.args))
+			cmd := exec.Command(os.Args[0], args...)
+			// 在临时目录中运行, 日志文件和用户缓存不影响仓库
+			cmd.Dir = workDir
+			cmd.Env = append(goldenEnviron(),
+				goldenMainEnv+"=1",
+				"HOME="+workDir,
+				"XDG_CONFIG_HOME="+workDir,
+				"XDG_CACHE_HOME="+workDir,
+				// 价格历史和进出港航班记录使用 testdata/history 中的记录（回放时不会写入）
+				historyDirEnv+"="+filepath.Join(testdata, "history"),
+			)
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			output, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("命令运行失败: %v\n%s", err, stderr.String())
+			}
+			golden := filepath.Join("testdata", "golden", tt.name+".txt")
+			if *updateGolden {
+				if err := ioutil.WriteFile(golden, output, 0644); err != nil {
+					t.Fatalf("更新预期输出失败: %v", err)
+				}
+				return
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("读取预期输出失败: %v", err)
+			}
+			if !bytes.Equal(output, want) {
+				t.Errorf("输出与 %s 不一致:\n%s\n期望:\n%s", golden, output, want)
+			}
+		})
+	}
+}
+
+// 去掉用户设置的接口地址等环境变量, 保证回放的请求与录制时一致
+func goldenEnviron() []string {
+	env := make([]string, 0)
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "FLIGHT_GO_") {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+const (
+	historyDirName string = "history"
+	historyDirEnv  string = "FLIGHT_GO_HISTORY_DIR"
+	// 价格历史按观测月份分文件保存（每行一条 JSON 记录）
+	fareHistoryFilePrefix string = "fares-"
+	// 进出港航班记录同样按观测月份分文件保存
+	boardHistoryFilePrefix string = "boards-"
+	historyFileSuffix      string = ".ndjson"
+)
+
+// 一次观测到的票价
+type FareObservation struct {
+	ObservedAt time.Time `json:"observedAt"`
+	// 国内/国际航线（domestic, international）
+	Market    string `json:"market"`
+	Departure string `json:"departure"`
+	Arrival   string `json:"arrival"`
+	// 出发日期（格式: YYYY-MM-DD）
+	Date          string            `json:"date"`
+	FlightNumber  string            `json:"flightNumber"`
+	AirlineName   string            `json:"airlineName,omitempty"`
+	DepartureTime flightgo.DateTime `json:"departureTime"`
+	CabinCode     string            `json:"cabinCode,omitempty"`
+	CabinName     string            `json:"cabinName"`
+	Price         int64             `json:"price"`
+	Tax           int64             `json:"tax,omitempty"`
+	Rate          float64           `json:"rate,omitempty"`
+	RestSeats     int64             `json:"restSeats,omitempty"`
+}
+
+// 含税总价
+func (o FareObservation) TotalPrice() int64 {
+	return o.Price + o.Tax
+}
+
+// 价格历史查询条件
+type FareHistoryFilter struct {
+	Departure    string
+	Arrival      string
+	Date         string
+	FlightNumber string
+	CabinName    string
+	// 观测时间范围（零值表示不限制）
+	Since time.Time
+	Until time.Time
+}
+
+// 是否满足查询条件
+func (f FareHistoryFilter) match(o FareObservation) bool {
+	switch {
+	case f.Departure != "" && o.Departure != f.Departure,
+		f.Arrival != "" && o.Arrival != f.Arrival,
+		f.Date != "" && o.Date != f.Date,
+		f.FlightNumber != "" && !strings.EqualFold(o.FlightNumber, f.FlightNumber),
+		f.CabinName != "" && o.CabinName != f.CabinName,
+		!f.Since.IsZero() && o.ObservedAt.Before(f.Since),
+		!f.Until.IsZero() && !o.ObservedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// 一次观测到的进出港航班
+type BoardObservation struct {
+	ObservedAt time.Time `json:"observedAt"`
+	flightgo.BoardEntry
+}
+
+// 进出港航班记录查询条件
+type BoardHistoryFilter struct {
+	// 查询的机场（按 IATA 代码匹配进出港航班的本地机场）
+	Airports []flightgo.Airport
+	// 进出港类别（为空时不限制）
+	Direction string
+	// 计划时间范围（零值表示不限制）
+	Since time.Time
+	Until time.Time
+}
+
+// 是否满足查询条件
+func (f BoardHistoryFilter) match(o BoardObservation) bool {
+	switch {
+	case f.Direction != "" && o.Direction != f.Direction,
+		!f.Since.IsZero() && o.ScheduledTime.Before(f.Since),
+		!f.Until.IsZero() && !o.ScheduledTime.Before(f.Until):
+		return false
+	}
+	if len(f.Airports) == 0 {
+		return true
+	}
+	local := o.LocalEnd()
+	for _, airport := range f.Airports {
+		if airport.IATA != "" && strings.EqualFold(local.IATA, airport.IATA) {
+			return true
+		}
+	}
+	return false
+}
+
+// 本地价格历史（纯文件存储, 按月份分文件追加写入）
+type HistoryStore struct {
+	Dir string
+}
+
+// 默认的历史数据目录（依次取 FLIGHT_GO_HISTORY_DIR 环境变量、配置文件中的 historyDir 和用户配置目录下的 flight-go/history）
+func defaultHistoryDir() string {
+	if dir := os.Getenv(historyDirEnv); dir != "" {
+		return dir
+	}
+	if config.HistoryDir != "" {
+		return config.HistoryDir
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return historyDirName
+	}
+	return filepath.Join(configDir, "flight-go", historyDirName)
+}
+
+func NewHistoryStore(dir string) *HistoryStore {
+	return &HistoryStore{Dir: dir}
+}
+
+// 追加记录（每条记录写入观测月份对应的文件）
+func (s *HistoryStore) appendRecords(prefix string, records []interface{}, observedAt func(int) time.Time) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	files := make(map[string]*os.File)
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+	for i, record := range records {
+		name := prefix + observedAt(i).Format("200601") + historyFileSuffix
+		file, ok := files[name]
+		if !ok {
+			var err error
+			file, err = os.OpenFile(filepath.Join(s.Dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			files[name] = file
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 按文件名顺序（即观测月份）逐行读取记录
+func (s *HistoryStore) scanRecords(prefix string, handle func(line []byte) error) error {
+	files, err := filepath.Glob(filepath.Join(s.Dir, prefix+"*"+historyFileSuffix))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for _, path := range files {
+		if err := scanHistoryFile(path, handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 逐行读取历史文件（跳过无法解析的行, 例如写入中断留下的半行）
+func scanHistoryFile(path string, handle func(line []byte) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || !json.Valid(line) {
+			continue
+		}
+		if err := handle(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// 追加票价记录
+func (s *HistoryStore) AppendFares(observations []FareObservation) error {
+	records := make([]interface{}, 0, len(observations))
+	for _, observation := range observations {
+		records = append(records, observation)
+	}
+	return s.appendRecords(fareHistoryFilePrefix, records, func(i int) time.Time {
+		return observations[i].ObservedAt
+	})
+}
+
+// 查询票价记录（按观测时间排序）
+func (s *HistoryStore) QueryFares(filter FareHistoryFilter) ([]FareObservation, error) {
+	observations := make([]FareObservation, 0)
+	err := s.scanRecords(fareHistoryFilePrefix, func(line []byte) error {
+		var observation FareObservation
+		if err := json.Unmarshal(line, &observation); err != nil {
+			return nil
+		}
+		if filter.match(observation) {
+			observations = append(observations, observation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].ObservedAt.Before(observations[j].ObservedAt)
+	})
+	return observations, nil
+}
+
+// 追加进出港航班记录
+func (s *HistoryStore) AppendBoard(observations []BoardObservation) error {
+	records := make([]interface{}, 0, len(observations))
+	for _, observation := range observations {
+		records = append(records, observation)
+	}
+	return s.appendRecords(boardHistoryFilePrefix, records, func(i int) time.Time {
+		return observations[i].ObservedAt
+	})
+}
+
+// 查询进出港航班记录（按观测时间排序）
+func (s *HistoryStore) QueryBoard(filter BoardHistoryFilter) ([]BoardObservation, error) {
+	observations := make([]BoardObservation, 0)
+	err := s.scanRecords(boardHistoryFilePrefix, func(line []byte) error {
+		var observation BoardObservation
+		if err := json.Unmarshal(line, &observation); err != nil {
+			return nil
+		}
+		if filter.match(observation) {
+			observations = append(observations, observation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].ObservedAt.Before(observations[j].ObservedAt)
+	})
+	return observations, nil
+}
+
+// 把进出港航班转换为观测记录
+func boardObservations(entries []flightgo.BoardEntry, observedAt time.Time) []BoardObservation {
+	observations := make([]BoardObservation, 0, len(entries))
+	for _, entry := range entries {
+		observations = append(observations, BoardObservation{ObservedAt: observedAt, BoardEntry: entry})
+	}
+	return observations
+}
+
+// 把查询结果转换为票价记录
+func fareObservations(market, departure, arrival, date string, itineraries []flightgo.Itinerary, observedAt time.Time) []FareObservation {
+	observations := make([]FareObservation, 0)
+	for _, itinerary := range itineraries {
+		if len(itinerary.Legs) == 0 {
+			continue
+		}
+		firstLeg := itinerary.Legs[0]
+		base := FareObservation{
+			ObservedAt:    observedAt,
+			Market:        market,
+			Departure:     departure,
+			Arrival:       arrival,
+			Date:          date,
+			FlightNumber:  itinerary.FlightNumbers(),
+			AirlineName:   firstLeg.AirlineName,
+			DepartureTime: firstLeg.DepartureTime,
+		}
+		// 国内航班按航段报价, 国际航班按整个行程报价
+		fares := itinerary.Fares
+		if len(fares) == 0 && len(itinerary.Legs) == 1 {
+			fares = firstLeg.Fares
+		}
+		for _, fare := range fares {
+			observation := base
+			observation.CabinCode = fare.Cabin.Code
+			observation.CabinName = fare.Cabin.Name
+			observation.Price = fare.Price
+			observation.Tax = fare.Tax
+			observation.Rate = fare.Rate
+			observation.RestSeats = fare.RestSeats
+			observations = append(observations, observation)
+		}
+	}
+	return observations
+}
+
+// 是否记录价格历史和进出港航班（-no-history 或回放时不记录）
+var historyDisabled bool
+
+// 记录查询结果到价格历史（失败时只记录日志, 不影响查询结果的输出）
+func recordFareHistory(market, departure, arrival, date string, itineraries []flightgo.Itinerary) {
+	if historyDisabled || cassetteReplayDir != "" {
+		return
+	}
+	observations := fareObservations(market, departure, arrival, date, itineraries, time.Now())
+	if err := NewHistoryStore(defaultHistoryDir()).AppendFares(observations); err != nil {
+		logger.Warnf("[Flight-Go]记录价格历史失败, 错误原因: %v", err)
+	}
+}
+
+// 记录进出港航班（用于 airport stats 汇总多次查询的准点率; 失败时只记录日志）
+func recordBoardHistory(entries []flightgo.BoardEntry) {
+	if historyDisabled || cassetteReplayDir != "" {
+		return
+	}
+	if err := NewHistoryStore(defaultHistoryDir()).AppendBoard(boardObservations(entries, time.Now())); err != nil {
+		logger.Warnf("[Flight-Go]记录进出港航班失败, 错误原因: %v", err)
+	}
+}
+
+// 记录多段行程每一段的查询结果
+func recordTripHistory(trip *flightgo.Trip) {
+	for _, segment := range trip.Segments {
+		recordFareHistory(MarketDomestic, segment.Departure, segment.Arrival, segment.Date, segment.Itineraries)
+	}
+}
+
+// 某条航线某个舱位等级和出发日期的价格统计
+type FareHistorySummary struct {
+	Market       string `json:"market"`
+	Departure    string `json:"departure"`
+	Arrival      string `json:"arrival"`
+	Date         string `json:"date"`
+	CabinName    string `json:"cabinName"`
+	Observations int    `json:"observations"`
+	// 每次查询的最低价格的统计（元, 含税）
+	Lowest  int64 `json:"lowest"`
+	Highest int64 `json:"highest"`
+	Average int64 `json:"average"`
+	Latest  int64 `json:"latest"`
+	// 最近一次最低价格在历史最低价格中的百分位（0 表示历史最低）
+	LatestPercentile float64   `json:"latestPercentile"`
+	FirstObservedAt  time.Time `json:"firstObservedAt"`
+	LastObservedAt   time.Time `json:"lastObservedAt"`
+}
+
+// 按航线（国内/国际、出发地和到达地）、出发日期和舱位等级汇总（每次查询只取该舱位等级的最低价格）
+func summarizeFareHistory(observations []FareObservation) []FareHistorySummary {
+	type groupKey struct{ market, departure, arrival, date, cabinName string }
+	// 每组中每次查询的最低价格（按观测时间排序）
+	lowestByObservation := make(map[groupKey][]FareObservation)
+	var keys []groupKey
+	for _, observation := range observations {
+		key := groupKey{observation.Market, observation.Departure, observation.Arrival, observation.Date, observation.CabinName}
+		group, ok := lowestByObservation[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+		if n := len(group); n > 0 && group[n-1].ObservedAt.Equal(observation.ObservedAt) {
+			if observation.TotalPrice() < group[n-1].TotalPrice() {
+				group[n-1] = observation
+			}
+		} else {
+			group = append(group, observation)
+		}
+		lowestByObservation[key] = group
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch {
+		case a.market != b.market:
+			return a.market < b.market
+		case a.departure != b.departure:
+			return a.departure < b.departure
+		case a.arrival != b.arrival:
+			return a.arrival < b.arrival
+		case a.date != b.date:
+			return a.date < b.date
+		}
+		return a.cabinName < b.cabinName
+	})
+	summaries := make([]FareHistorySummary, 0, len(keys))
+	for _, key := range keys {
+		group := lowestByObservation[key]
+		summary := FareHistorySummary{
+			Market:          key.market,
+			Departure:       key.departure,
+			Arrival:         key.arrival,
+			Date:            key.date,
+			CabinName:       key.cabinName,
+			Observations:    len(group),
+			Lowest:          math.MaxInt64,
+			FirstObservedAt: group[0].ObservedAt,
+			LastObservedAt:  group[len(group)-1].ObservedAt,
+			Latest:          group[len(group)-1].TotalPrice(),
+		}
+		var total int64
+		lower := 0
+		for _, observation := range group {
+			price := observation.TotalPrice()
+			total += price
+			if price < summary.Lowest {
+				summary.Lowest = price
+			}
+			if price > summary.Highest {
+				summary.Highest = price
+			}
+			if price < summary.Latest {
+				lower++
+			}
+		}
+		summary.Average = total / int64(len(group))
+		summary.LatestPercentile = float64(lower) / float64(len(group)) * 100
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// 解析日期参数（格式: YYYY-MM-DD, 北京时间）
+func parseHistoryDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, flightgo.ChinaLocation)
+	if err != nil {
+		return time.Time{}, flightgo.NewInvalidArgumentError("%s 日期格式错误: %s（格式: YYYY-MM-DD）", name, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+// 2019-11-14 某个时刻的观测时间
+func testObservedAt(hour int) time.Time {
+	return time.Date(2019, 11, 14, hour, 0, 0, 0, testLocation)
+}
+
+func testObservation(hour int, date, cabinName string, price int64) FareObservation {
+	return FareObservation{
+		ObservedAt: testObservedAt(hour),
+		Market:     MarketDomestic,
+		Departure:  "北京",
+		Arrival:    "上海",
+		Date:       date,
+		CabinName:  cabinName,
+		Price:      price,
+	}
+}
+
+func TestFareObservations(t *testing.T) {
+	itineraries := []flightgo.Itinerary{
+		{Legs: []flightgo.Leg{{FlightNumber: "CA1501", AirlineName: "中国国际航空", Fares: []flightgo.Fare{
+			{Cabin: flightgo.Cabin{Code: "Y", Name: "经济舱"}, Price: 880, Rate: 0.7, RestSeats: 9},
+			{Cabin: flightgo.Cabin{Code: "F", Name: "头等舱"}, Price: 5600},
+		}}}},
+		// 国际航班按整个行程报价
+		{
+			Legs:  []flightgo.Leg{{FlightNumber: "KE856"}, {FlightNumber: "KE703"}},
+			Fares: []flightgo.Fare{{Cabin: flightgo.Cabin{Code: "y_s", Name: "经济舱"}, Price: 1420, Tax: 610}},
+		},
+		// 没有航段的行程不记录
+		{},
+	}
+	observations := fareObservations(MarketDomestic, "北京", "上海", "2019-11-15", itineraries, testObservedAt(9))
+	tests := []struct {
+		flightNumber string
+		cabinCode    string
+		totalPrice   int64
+	}{
+		{"CA1501", "Y", 880},
+		{"CA1501", "F", 5600},
+		{"KE856/KE703", "y_s", 2030},
+	}
+	if len(observations) != len(tests) {
+		t.Fatalf("记录数量: %d, 期望 %d", len(observations), len(tests))
+	}
+	for i, tt := range tests {
+		observation := observations[i]
+		if observation.FlightNumber != tt.flightNumber || observation.CabinCode != tt.cabinCode || observation.TotalPrice() != tt.totalPrice {
+			t.Errorf("第 %d 条: %s %s %d, 期望 %s %s %d", i+1, observation.FlightNumber, observation.CabinCode, observation.TotalPrice(), tt.flightNumber, tt.cabinCode, tt.totalPrice)
+		}
+		if observation.Departure != "北京" || observation.Date != "2019-11-15" || !observation.ObservedAt.Equal(testObservedAt(9)) {
+			t.Errorf("第 %d 条: %+v", i+1, observation)
+		}
+	}
+}
+
+func TestHistoryStore(t *testing.T) {
+	store := NewHistoryStore(t.TempDir())
+	observations := []FareObservation{
+		testObservation(9, "2019-11-15", "经济舱", 880),
+		testObservation(10, "2019-11-15", "头等舱", 5600),
+		// 不同月份的观测写入不同文件
+		{ObservedAt: time.Date(2019, 12, 1, 9, 0, 0, 0, testLocation), Departure: "北京", Arrival: "上海", Date: "2019-12-15", CabinName: "经济舱", Price: 760},
+	}
+	if err := store.AppendFares(observations); err != nil {
+		t.Fatalf("写入价格历史失败: %v", err)
+	}
+	tests := []struct {
+		name   string
+		filter FareHistoryFilter
+		prices []int64
+	}{
+		{"全部", FareHistoryFilter{}, []int64{880, 5600, 760}},
+		{"舱位等级", FareHistoryFilter{CabinName: "经济舱"}, []int64{880, 760}},
+		{"出发日期", FareHistoryFilter{Date: "2019-12-15"}, []int64{760}},
+		{"观测时间范围", FareHistoryFilter{Since: testObservedAt(10), Until: testObservedAt(11)}, []int64{5600}},
+		{"航线不匹配", FareHistoryFilter{Departure: "上海"}, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := store.QueryFares(tt.filter)
+			if err != nil {
+				t.Fatalf("查询价格历史失败: %v", err)
+			}
+			prices := make([]int64, 0, len(found))
+			for _, observation := range found {
+				prices = append(prices, observation.Price)
+			}
+			if len(prices) != len(tt.prices) {
+				t.Fatalf("价格: %v, 期望 %v", prices, tt.prices)
+			}
+			for i := range prices {
+				if prices[i] != tt.prices[i] {
+					t.Errorf("价格: %v, 期望 %v", prices, tt.prices)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeFareHistory(t *testing.T) {
+	observations := []FareObservation{
+		// 同一次查询只取最低价格
+		testObservation(9, "2019-11-15", "经济舱", 900),
+		testObservation(9, "2019-11-15", "经济舱", 880),
+		testObservation(9, "2019-11-15", "头等舱", 5600),
+		testObservation(12, "2019-11-15", "经济舱", 700),
+		testObservation(15, "2019-11-15", "经济舱", 760),
+		testObservation(12, "2019-11-14", "经济舱", 650),
+	}
+	// 同一天同一舱位的其他航线和国际航线分别汇总
+	reverse := testObservation(9, "2019-11-15", "经济舱", 820)
+	reverse.Departure, reverse.Arrival = "上海", "北京"
+	international := testObservation(9, "2019-11-15", "经济舱", 1650)
+	international.Market = MarketInternational
+	observations = append(observations, reverse, international)
+	tests := []struct {
+		market, departure            string
+		date, cabinName              string
+		observations                 int
+		lowest, highest, avg, latest int64
+		percentile                   float64
+	}{
+		{MarketDomestic, "上海", "2019-11-15", "经济舱", 1, 820, 820, 820, 820, 0},
+		{MarketDomestic, "北京", "2019-11-14", "经济舱", 1, 650, 650, 650, 650, 0},
+		{MarketDomestic, "北京", "2019-11-15", "头等舱", 1, 5600, 5600, 5600, 5600, 0},
+		// 3 次查询的最低价格为 880、700、760, 最近一次高于其中 1 次
+		{MarketDomestic, "北京", "2019-11-15", "经济舱", 3, 700, 880, 780, 760, 33.3},
+		{MarketInternational, "北京", "2019-11-15", "经济舱", 1, 1650, 1650, 1650, 1650, 0},
+	}
+	summaries := summarizeFareHistory(observations)
+	if len(summaries) != len(tests) {
+		t.Fatalf("汇总数量: %d, 期望 %d", len(summaries), len(tests))
+	}
+	for i, tt := range tests {
+		summary := summaries[i]
+		if summary.Market != tt.market || summary.Departure != tt.departure || summary.Date != tt.date || summary.CabinName != tt.cabinName || summary.Observations != tt.observations {
+			t.Errorf("第 %d 组: %s %s %s %s %d, 期望 %s %s %s %s %d", i+1, summary.Market, summary.Departure, summary.Date, summary.CabinName, summary.Observations,
+				tt.market, tt.departure, tt.date, tt.cabinName, tt.observations)
+		}
+		if summary.Lowest != tt.lowest || summary.Highest != tt.highest || summary.Average != tt.avg || summary.Latest != tt.latest {
+			t.Errorf("第 %d 组价格: %+v", i+1, summary)
+		}
+		if math.Abs(summary.LatestPercentile-tt.percentile) > 0.1 {
+			t.Errorf("第 %d 组百分位: %v, 期望 %v", i+1, summary.LatestPercentile, tt.percentile)
+		}
+	}
+}
+
+func TestParseHistoryDate(t *testing.T) {
+	if date, err := parseHistoryDate("-since", ""); err != nil || !date.IsZero() {
+		t.Errorf("空日期: %s（%v）", date, err)
+	}
+	if date, err := parseHistoryDate("-since", "2019-11-14"); err != nil || date.Format("2006-01-02") != "2019-11-14" {
+		t.Errorf("日期: %s（%v）", date, err)
+	}
+	if _, err := parseHistoryDate("-since", "20191114"); !flightgo.IsErrorKind(err, flightgo.ErrorInvalidArgument) {
+		t.Errorf("日期格式错误: %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+// serve 命令的 OpenAPI 接口描述（GET /openapi.json）
+const openAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "Flight-Go API",
+    "description": "国内/国际机票价格、航班动态和机场进出港信息查询接口",
+    "version": "v0.1.0"
+  },
+  "paths": {
+    "/api/v1/domestic": {
+      "get": {
+        "summary": "查询国内单程航班",
+        "operationId": "searchDomestic",
+        "parameters": [
+          {"$ref": "#/components/parameters/departure"},
+          {"$ref": "#/components/parameters/arrival"},
+          {"$ref": "#/components/parameters/date"},
+          {"$ref": "#/components/parameters/airline"},
+          {"$ref": "#/components/parameters/flightNumber"},
+          {"$ref": "#/components/parameters/departWindow"},
+          {"$ref": "#/components/parameters/arriveWindow"},
+          {"$ref": "#/components/parameters/aircraft"},
+          {"name": "meal", "in": "query", "description": "只保留每个航段都提供餐食的航班", "schema": {"type": "boolean"}},
+          {"name": "minPunctuality", "in": "query", "description": "最低准点率（百分比, 例如: 80）", "schema": {"type": "number", "minimum": 0}},
+          {"name": "cabin", "in": "query", "description": "只保留该舱位等级的票价（例如: 经济舱）", "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/maxPrice"},
+          {"$ref": "#/components/parameters/maxStops"},
+          {"$ref": "#/components/parameters/maxLayover"},
+          {"$ref": "#/components/parameters/sort"}
+        ],
+        "responses": {
+          "200": {"$ref": "#/components/responses/Itineraries"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/international": {
+      "get": {
+        "summary": "查询国际航班",
+        "operationId": "searchInternational",
+        "parameters": [
+          {"$ref": "#/components/parameters/departure"},
+          {"$ref": "#/components/parameters/arrival"},
+          {"$ref": "#/components/parameters/date"},
+          {"name": "cabin", "in": "query", "description": "查询的舱位等级（经济舱，超级经济舱，商务/头等舱，商务舱，公务舱，头等舱; 默认: 经济舱）", "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/airline"},
+          {"$ref": "#/components/parameters/flightNumber"},
+          {"$ref": "#/components/parameters/departWindow"},
+          {"$ref": "#/components/parameters/arriveWindow"},
+          {"$ref": "#/components/parameters/aircraft"},
+          {"$ref": "#/components/parameters/maxPrice"},
+          {"$ref": "#/components/parameters/maxStops"},
+          {"$ref": "#/components/parameters/maxLayover"},
+          {"$ref": "#/components/parameters/sort"}
+        ],
+        "responses": {
+          "200": {"$ref": "#/components/responses/Itineraries"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/flight-status": {
+      "get": {
+        "summary": "查询航班动态",
+        "operationId": "flightStatus",
+        "parameters": [
+          {"name": "flightNumber", "in": "query", "required": true, "description": "航班号（例如: CA1501）", "schema": {"type": "string"}},
+          {"name": "date", "in": "query", "required": true, "description": "日期（格式: YYYYMMDD 或 YYYY-MM-DD）", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "航班动态（经停航班每个航段一条）",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/FlightStatus"}}}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/airport-board": {
+      "get": {
+        "summary": "查询机场进出港航班",
+        "operationId": "airportBoard",
+        "parameters": [
+          {"name": "airport", "in": "query", "required": true, "description": "城市名、机场名或机场代码（例如: 北京、北京大兴、PKX、ZBAD）; 城市名会合并该城市全部机场的航班", "schema": {"type": "string"}},
+          {"name": "direction", "in": "query", "description": "进出港类别（dep: 出港; arr: 进港; 默认: dep）", "schema": {"type": "string", "enum": ["dep", "arr"]}},
+          {"name": "date", "in": "query", "description": "日期（格式: YYYY-MM-DD; 默认不限制）, 只保留接口返回的航班中计划时间在该日期的航班", "schema": {"type": "string", "format": "date"}},
+          {"name": "window", "in": "query", "description": "只保留该时间段的航班（格式: HH:MM-HH:MM; 计划或实际/预计时间在时间段内即可）", "schema": {"type": "string"}},
+          {"name": "next", "in": "query", "description": "只保留从现在开始该分钟数内的航班（不能和 window 同时使用）", "schema": {"type": "integer", "minimum": 0}},
+          {"$ref": "#/components/parameters/flightNumber"},
+          {"name": "airline", "in": "query", "description": "航空公司代码（航班号前缀）, 多个以逗号分隔（例如: CZ,MU）", "schema": {"type": "string"}},
+          {"name": "city", "in": "query", "description": "出港航班的到达地或进港航班的出发地（城市名或机场名）", "schema": {"type": "string"}},
+          {"name": "status", "in": "query", "description": "航班状态, 多个以逗号分隔（阶段标识或中文名称, 例如: delayed,cancelled 或 延误; 其他文字按状态名称包含匹配）", "schema": {"type": "string"}},
+          {"name": "aircraft", "in": "query", "description": "机型（例如: 320）", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "进出港航班",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/BoardEntry"}}}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/healthz": {
+      "get": {
+        "summary": "健康检查",
+        "operationId": "health",
+        "responses": {
+          "200": {
+            "description": "服务正常",
+            "content": {"application/json": {"schema": {"type": "object", "properties": {"status": {"type": "string"}, "version": {"type": "string"}}}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "departure": {"name": "departure", "in": "query", "required": true, "description": "出发地（城市名, 例如: 北京）", "schema": {"type": "string"}},
+      "arrival": {"name": "arrival", "in": "query", "required": true, "description": "到达地（城市名, 例如: 上海）", "schema": {"type": "string"}},
+      "date": {"name": "date", "in": "query", "required": true, "description": "出发日期（格式: YYYY-MM-DD）", "schema": {"type": "string", "format": "date"}},
+      "airline": {"name": "airline", "in": "query", "description": "航空公司名称或航班号前缀, 多个以逗号分隔（例如: 东方航空 或 MU,CA）", "schema": {"type": "string"}},
+      "departWindow": {"name": "departWindow", "in": "query", "description": "起飞时间段（格式: HH:MM-HH:MM, 例如: 06:00-12:00; 开始时间晚于结束时间时表示跨过午夜）", "schema": {"type": "string"}},
+      "arriveWindow": {"name": "arriveWindow", "in": "query", "description": "到达时间段（格式: HH:MM-HH:MM）", "schema": {"type": "string"}},
+      "aircraft": {"name": "aircraft", "in": "query", "description": "机型名称或代码（例如: 320）", "schema": {"type": "string"}},
+      "maxStops": {"name": "maxStops", "in": "query", "description": "最多中转次数（0 为只保留直飞）", "schema": {"type": "integer", "minimum": 0}},
+      "maxLayover": {"name": "maxLayover", "in": "query", "description": "最长转机等待时间（分钟）", "schema": {"type": "integer", "minimum": 0}},
+      "sort": {"name": "sort", "in": "query", "description": "排序方式（格式: 字段[:asc|desc], 例如 price 或 arrival:desc; 没有对应数据的航班排在最后）", "schema": {"type": "string", "pattern": "^(price|economy|business|first|departure|arrival|duration|punctuality|stops)(:(asc|desc))?$"}},
+      "flightNumber": {"name": "flightNumber", "in": "query", "description": "只保留该航班号", "schema": {"type": "string"}},
+      "maxPrice": {"name": "maxPrice", "in": "query", "description": "最高含税总价（元）", "schema": {"type": "integer", "minimum": 0}}
+    },
+    "responses": {
+      "Itineraries": {
+        "description": "航班行程",
+        "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Itinerary"}}}}
+      },
+      "Error": {
+        "description": "错误（400: 参数错误; 404: 未知的城市或机场; 502: 接口请求或解析失败; 503: 被反爬虫拦截; 504: 查询超时）",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      }
+    },
+    "schemas": {
+      "Airport": {
+        "type": "object",
+        "properties": {
+          "countryName": {"type": "string"},
+          "cityName": {"type": "string"},
+          "name": {"type": "string"},
+          "iata": {"type": "string"},
+          "icao": {"type": "string"},
+          "terminal": {"type": "string"}
+        }
+      },
+      "Cabin": {
+        "type": "object",
+        "properties": {
+          "code": {"type": "string"},
+          "name": {"type": "string"}
+        }
+      },
+      "Fare": {
+        "type": "object",
+        "properties": {
+          "cabin": {"$ref": "#/components/schemas/Cabin"},
+          "price": {"type": "integer", "description": "票价（元, 不含税）"},
+          "tax": {"type": "integer", "description": "税费（元, 仅国际航班）"},
+          "rate": {"type": "number", "description": "折扣（1.0 为无折扣）"},
+          "restSeats": {"type": "integer"}
+        }
+      },
+      "Leg": {
+        "type": "object",
+        "properties": {
+          "airlineName": {"type": "string"},
+          "flightNumber": {"type": "string"},
+          "departure": {"$ref": "#/components/schemas/Airport"},
+          "departureTime": {"type": "string", "format": "date-time", "nullable": true},
+          "arrival": {"$ref": "#/components/schemas/Airport"},
+          "arrivalTime": {"type": "string", "format": "date-time", "nullable": true},
+          "aircraftName": {"type": "string"},
+          "aircraftCode": {"type": "string"},
+          "hasMeal": {"type": "boolean"},
+          "punctualityRate": {"type": "string"},
+          "duration": {"type": "integer", "description": "飞行时长（分钟）"},
+          "transferDuration": {"type": "integer", "description": "转机等待时长（分钟）"},
+          "fares": {"type": "array", "description": "航段票价（国内航班按航段报价）", "items": {"$ref": "#/components/schemas/Fare"}}
+        }
+      },
+      "Itinerary": {
+        "type": "object",
+        "properties": {
+          "legs": {"type": "array", "items": {"$ref": "#/components/schemas/Leg"}},
+          "duration": {"type": "integer", "description": "总飞行时长（分钟）"},
+          "fares": {"type": "array", "description": "行程票价（国际航班按整个行程报价）", "items": {"$ref": "#/components/schemas/Fare"}}
+        }
+      },
+      "FlightPhase": {
+        "type": "string",
+        "description": "航班所处阶段（未知状态码为 unknown）",
+        "enum": ["scheduled", "boarding", "departed", "in-air", "landed", "delayed", "diverted", "returned", "cancelled", "unknown"]
+      },
+      "FlightStatus": {
+        "type": "object",
+        "properties": {
+          "flightNumber": {"type": "string"},
+          "statusCode": {"type": "integer"},
+          "status": {"type": "string", "description": "状态名称（未知状态码为 \"未知 (状态码)\"）"},
+          "phase": {"$ref": "#/components/schemas/FlightPhase"},
+          "departure": {"$ref": "#/components/schemas/Airport"},
+          "arrival": {"$ref": "#/components/schemas/Airport"},
+          "scheduledDepartureTime": {"type": "string", "format": "date-time", "nullable": true},
+          "actualDepartureTime": {"type": "string", "format": "date-time", "nullable": true},
+          "scheduledArrivalTime": {"type": "string", "format": "date-time", "nullable": true},
+          "actualArrivalTime": {"type": "string", "format": "date-time", "nullable": true},
+          "aircraftType": {"type": "string"},
+          "aircraftNumber": {"type": "string"}
+        }
+      },
+      "BoardEntry": {
+        "type": "object",
+        "properties": {
+          "direction": {"type": "string", "enum": ["dep", "arr"]},
+          "flightNumber": {"type": "string"},
+          "aircraftType": {"type": "string"},
+          "statusCode": {"type": "integer"},
+          "status": {"type": "string", "description": "状态名称（未知状态码为 \"未知 (状态码)\"）"},
+          "phase": {"$ref": "#/components/schemas/FlightPhase"},
+          "departure": {"$ref": "#/components/schemas/Airport"},
+          "arrival": {"$ref": "#/components/schemas/Airport"},
+          "scheduledTime": {"type": "string", "format": "date-time", "nullable": true},
+          "actualTime": {"type": "string", "format": "date-time", "nullable": true},
+          "estimatedTime": {"type": "string", "format": "date-time", "nullable": true}
+        }
+      },
+      "ErrorResponse": {
+        "type": "object",
+        "properties": {
+          "error": {
+            "type": "object",
+            "properties": {
+              "code": {"type": "string", "enum": ["invalid_argument", "unknown_city", "network", "upstream_empty", "parse", "anti_bot_blocked", "timeout", "method_not_allowed", "unknown"]},
+              "message": {"type": "string"}
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+// 输出格式
+const (
+	OutputTable  string = "table"
+	OutputJSON   string = "json"
+	OutputNDJSON string = "ndjson"
+	OutputCSV    string = "csv"
+)
+
+// 校验输出格式
+func checkOutputFormat(format string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputNDJSON, OutputCSV:
+		return nil
+	}
+	return fmt.Errorf("不支持的输出格式: %s（可选: table, json, ndjson, csv）", format)
+}
+
+// 输出 JSON（整个结果集为一个数组）
+func writeJSON(w io.Writer, records []interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// 输出 NDJSON（每行一条记录）
+func writeNDJSON(w io.Writer, records []interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 输出 CSV
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// 按格式输出结果（JSON 和 NDJSON 输出完整结构, CSV 输出展开后的行）
+func writeRecords(w io.Writer, format string, records []interface{}, csvHeader []string, csvRows func() [][]string) error {
+	switch format {
+	case OutputJSON:
+		return writeJSON(w, records)
+	case OutputNDJSON:
+		return writeNDJSON(w, records)
+	case OutputCSV:
+		return writeCSV(w, csvHeader, csvRows())
+	}
+	return checkOutputFormat(format)
+}
+
+var itineraryCSVHeader = []string{
+	"itinerary", "leg", "airline_name", "flight_number",
+	"departure_country", "departure_city", "departure_airport", "departure_terminal", "departure_time",
+	"arrival_country", "arrival_city", "arrival_airport", "arrival_terminal", "arrival_time",
+	"aircraft_name", "aircraft_code", "has_meal", "punctuality_rate", "duration_minutes", "transfer_minutes",
+	"itinerary_duration_minutes", "cabin_code", "cabin_name", "price", "tax", "rate", "rest_seats",
+}
+
+// 输出航班行程（CSV 每个航段的每个票价一行, 国际航班的行程票价会在每个航段重复）
+func writeItineraries(w io.Writer, format string, itineraries []flightgo.Itinerary) error {
+	records := make([]interface{}, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		records = append(records, itinerary)
+	}
+	return writeRecords(w, format, records, itineraryCSVHeader, func() [][]string {
+		return itineraryCSVRows(itineraries)
+	})
+}
+
+// 航班行程的 CSV 行
+func itineraryCSVRows(itineraries []flightgo.Itinerary) [][]string {
+	rows := make([][]string, 0)
+	for i, itinerary := range itineraries {
+		for j, leg := range itinerary.Legs {
+			legColumns := []string{
+				strconv.Itoa(i + 1), strconv.Itoa(j + 1), leg.AirlineName, leg.FlightNumber,
+				leg.Departure.CountryName, leg.Departure.CityName, leg.Departure.Name, leg.Departure.Terminal, leg.DepartureTime.String(),
+				leg.Arrival.CountryName, leg.Arrival.CityName, leg.Arrival.Name, leg.Arrival.Terminal, leg.ArrivalTime.String(),
+				leg.AircraftName, leg.AircraftCode, strconv.FormatBool(leg.HasMeal), leg.PunctualityRate,
+				strconv.FormatInt(leg.Duration, 10), strconv.FormatInt(leg.TransferDuration, 10),
+				strconv.FormatInt(itinerary.Duration, 10),
+			}
+			fares := leg.Fares
+			if len(fares) == 0 {
+				fares = itinerary.Fares
+			}
+			if len(fares) == 0 {
+				rows = append(rows, append(legColumns, "", "", "", "", "", ""))
+				continue
+			}
+			for _, fare := range fares {
+				row := append(append([]string{}, legColumns...),
+					fare.Cabin.Code, fare.Cabin.Name,
+					strconv.FormatInt(fare.Price, 10), strconv.FormatInt(fare.Tax, 10),
+					strconv.FormatFloat(fare.Rate, 'f', -1, 64), strconv.FormatInt(fare.RestSeats, 10),
+				)
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
+var tripCSVHeader = append([]string{"segment"}, itineraryCSVHeader...)
+
+// 输出多段行程（JSON 和 NDJSON 输出完整行程和最低价组合, CSV 每行前加上段序号）
+func writeTrip(w io.Writer, format string, trip *flightgo.Trip) error {
+	return writeRecords(w, format, []interface{}{trip}, tripCSVHeader, func() [][]string {
+		rows := make([][]string, 0)
+		for i, segment := range trip.Segments {
+			for _, row := range itineraryCSVRows(segment.Itineraries) {
+				rows = append(rows, append([]string{strconv.Itoa(i + 1)}, row...))
+			}
+		}
+		return rows
+	})
+}
+
+var fareCalendarCSVHeader = []string{"date", "flights", "economy", "business", "first", "error"}
+
+// 输出价格日历（每天一条记录）
+func writeFareCalendar(w io.Writer, format string, days []flightgo.FareCalendarDay) error {
+	records := make([]interface{}, 0, len(days))
+	for _, day := range days {
+		records = append(records, day)
+	}
+	return writeRecords(w, format, records, fareCalendarCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(days))
+		for _, day := range days {
+			rows = append(rows, []string{
+				day.Date, strconv.Itoa(day.Flights), strconv.FormatInt(day.Economy, 10),
+				strconv.FormatInt(day.Business, 10), strconv.FormatInt(day.First, 10), day.Error,
+			})
+		}
+		return rows
+	})
+}
+
+var fareObservationCSVHeader = []string{
+	"observed_at", "market", "departure", "arrival", "date", "flight_number", "airline_name", "departure_time",
+	"cabin_code", "cabin_name", "price", "tax", "rate", "rest_seats",
+}
+
+// 输出价格历史
+func writeFareObservations(w io.Writer, format string, observations []FareObservation) error {
+	records := make([]interface{}, 0, len(observations))
+	for _, observation := range observations {
+		records = append(records, observation)
+	}
+	return writeRecords(w, format, records, fareObservationCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(observations))
+		for _, o := range observations {
+			rows = append(rows, []string{
+				o.ObservedAt.Format(time.RFC3339), o.Market, o.Departure, o.Arrival, o.Date, o.FlightNumber, o.AirlineName,
+				o.DepartureTime.String(), o.CabinCode, o.CabinName, strconv.FormatInt(o.Price, 10), strconv.FormatInt(o.Tax, 10),
+				strconv.FormatFloat(o.Rate, 'f', -1, 64), strconv.FormatInt(o.RestSeats, 10),
+			})
+		}
+		return rows
+	})
+}
+
+var fareHistorySummaryCSVHeader = []string{
+	"market", "departure", "arrival", "date", "cabin_name", "observations", "lowest", "highest", "average", "latest", "latest_percentile",
+	"first_observed_at", "last_observed_at",
+}
+
+// 输出价格历史统计
+func writeFareHistorySummaries(w io.Writer, format string, summaries []FareHistorySummary) error {
+	records := make([]interface{}, 0, len(summaries))
+	for _, summary := range summaries {
+		records = append(records, summary)
+	}
+	return writeRecords(w, format, records, fareHistorySummaryCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(summaries))
+		for _, s := range summaries {
+			rows = append(rows, []string{
+				s.Market, s.Departure, s.Arrival, s.Date, s.CabinName, strconv.Itoa(s.Observations), strconv.FormatInt(s.Lowest, 10),
+				strconv.FormatInt(s.Highest, 10), strconv.FormatInt(s.Average, 10), strconv.FormatInt(s.Latest, 10),
+				strconv.FormatFloat(s.LatestPercentile, 'f', 1, 64),
+				s.FirstObservedAt.Format(time.RFC3339), s.LastObservedAt.Format(time.RFC3339),
+			})
+		}
+		return rows
+	})
+}
+
+var flightStatusCSVHeader = []string{
+	"flight_number", "status_code", "status", "phase", "departure_airport", "arrival_airport",
+	"scheduled_departure_time", "actual_departure_time", "scheduled_arrival_time", "actual_arrival_time",
+	"aircraft_type", "aircraft_number",
+}
+
+// 输出航班动态
+func writeFlightStatuses(w io.Writer, format string, statuses []flightgo.FlightStatus) error {
+	records := make([]interface{}, 0, len(statuses))
+	for _, status := range statuses {
+		records = append(records, status)
+	}
+	return writeRecords(w, format, records, flightStatusCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(statuses))
+		for _, status := range statuses {
+			rows = append(rows, []string{
+				status.FlightNumber, strconv.FormatInt(status.StatusCode, 10), status.Status, string(status.Phase),
+				status.Departure.Name, status.Arrival.Name,
+				status.ScheduledDepartureTime.String(), status.ActualDepartureTime.String(),
+				status.ScheduledArrivalTime.String(), status.ActualArrivalTime.String(),
+				status.AircraftType, status.AircraftNumber,
+			})
+		}
+		return rows
+	})
+}
+
+var boardEntryCSVHeader = []string{
+	"direction", "flight_number", "aircraft_type", "status_code", "status", "phase",
+	"departure_city", "departure_airport", "arrival_city", "arrival_airport",
+	"scheduled_time", "actual_time", "estimated_time",
+}
+
+// 输出机场进出港航班
+func writeBoardEntries(w io.Writer, format string, entries []flightgo.BoardEntry) error {
+	records := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, entry)
+	}
+	return writeRecords(w, format, records, boardEntryCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(entries))
+		for _, entry := range entries {
+			rows = append(rows, []string{
+				entry.Direction, entry.FlightNumber, entry.AircraftType,
+				strconv.FormatInt(entry.StatusCode, 10), entry.Status, string(entry.Phase),
+				entry.Departure.CityName, entry.Departure.Name, entry.Arrival.CityName, entry.Arrival.Name,
+				entry.ScheduledTime.String(), entry.ActualTime.String(), entry.EstimatedTime.String(),
+			})
+		}
+		return rows
+	})
+}
+
+var boardStatsCSVHeader = []string{
+	"group", "name", "flights", "completed", "on_time", "on_time_percent", "average_delay_minutes", "p90_delay_minutes", "cancelled",
+}
+
+// 输出进出港航班准点率统计（机场、航空公司、航线依次排列）
+func writeBoardStats(w io.Writer, format string, stats []BoardStats) error {
+	records := make([]interface{}, 0, len(stats))
+	for _, s := range stats {
+		records = append(records, s)
+	}
+	return writeRecords(w, format, records, boardStatsCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(stats))
+		for _, s := range stats {
+			rows = append(rows, []string{
+				s.Group, s.Name, strconv.Itoa(s.Flights), strconv.Itoa(s.Completed), strconv.Itoa(s.OnTime),
+				strconv.FormatFloat(s.OnTimePercent, 'f', 1, 64), strconv.FormatFloat(s.AverageDelay, 'f', 1, 64),
+				strconv.FormatInt(s.P90Delay, 10), strconv.Itoa(s.Cancelled),
+			})
+		}
+		return rows
+	})
+}
+
+var flightStatusCodeCSVHeader = []string{
+	"code", "name", "phase", "source", "observations", "last_observed_at", "last_flight_number",
+}
+
+// 输出航班状态码映射
+func writeFlightStatusCodeEntries(w io.Writer, format string, entries []FlightStatusCodeEntry) error {
+	records := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, entry)
+	}
+	return writeRecords(w, format, records, flightStatusCodeCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(entries))
+		for _, entry := range entries {
+			lastObservedAt := ""
+			if entry.LastObservedAt != nil {
+				lastObservedAt = entry.LastObservedAt.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{
+				strconv.FormatInt(entry.Code, 10), entry.Name, string(entry.Phase), entry.Source,
+				strconv.Itoa(entry.Observations), lastObservedAt, entry.LastFlightNumber,
+			})
+		}
+		return rows
+	})
+}
+
+var unknownFlightStatusCSVHeader = []string{
+	"observed_at", "provider", "code", "flight_number", "departure_airport", "arrival_airport", "raw",
+}
+
+// 输出未知状态码日志
+func writeUnknownFlightStatuses(w io.Writer, format string, unknowns []flightgo.UnknownFlightStatus) error {
+	records := make([]interface{}, 0, len(unknowns))
+	for _, unknown := range unknowns {
+		records = append(records, unknown)
+	}
+	return writeRecords(w, format, records, unknownFlightStatusCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(unknowns))
+		for _, u := range unknowns {
+			rows = append(rows, []string{
+				u.ObservedAt.Format(time.RFC3339), u.Provider, strconv.FormatInt(u.Code, 10),
+				u.FlightNumber, u.Departure, u.Arrival, string(u.Raw),
+			})
+		}
+		return rows
+	})
+}
+
+var cityCSVHeader = []string{"name", "code", "airports"}
+
+// 输出城市数据（CSV 中机场格式为 IATA/ICAO 名称, 多个机场以分号分隔）
+func writeCities(w io.Writer, format string, cities []flightgo.City) error {
+	records := make([]interface{}, 0, len(cities))
+	for _, city := range cities {
+		records = append(records, city)
+	}
+	return writeRecords(w, format, records, cityCSVHeader, func() [][]string {
+		rows := make([][]string, 0, len(cities))
+		for _, city := range cities {
+			rows = append(rows, []string{city.Name, city.Code, airportCodesString(city.Airports)})
+		}
+		return rows
+	})
+}
+
+// 机场代码展示（例如: PEK/ZBAA 首都国际机场;PKX/ZBAD 大兴国际机场）
+func airportCodesString(airports []flightgo.Airport) string {
+	codes := make([]string, 0, len(airports))
+	for _, airport := range airports {
+		codes = append(codes, fmt.Sprintf("%s/%s %s", airport.IATA, airport.ICAO, airport.Name))
+	}
+	return strings.Join(codes, ";")
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+var testLocation = time.FixedZone("CST", 8*60*60)
+
+func testDateTime(hour, min int) flightgo.DateTime {
+	return flightgo.DateTime{Time: time.Date(2019, 11, 15, hour, min, 0, 0, testLocation)}
+}
+
+func TestCheckOutputFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		valid  bool
+	}{
+		{OutputTable, true},
+		{OutputJSON, true},
+		{OutputNDJSON, true},
+		{OutputCSV, true},
+		{"xml", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if err := checkOutputFormat(tt.format); (err == nil) != tt.valid {
+			t.Errorf("checkOutputFormat(%q) = %v, 期望合法: %v", tt.format, err, tt.valid)
+		}
+	}
+}
+
+func TestWriteItineraries(t *testing.T) {
+	domestic := flightgo.Itinerary{Legs: []flightgo.Leg{{
+		AirlineName:   "中国国际航空",
+		FlightNumber:  "CA1501",
+		Departure:     flightgo.Airport{CityName: "北京", Name: "首都国际机场", Terminal: "T3"},
+		DepartureTime: testDateTime(8, 30),
+		Arrival:       flightgo.Airport{CityName: "上海", Name: "虹桥国际机场", Terminal: "T2"},
+		ArrivalTime:   testDateTime(10, 40),
+		Fares: []flightgo.Fare{
+			{Cabin: flightgo.Cabin{Code: "Y", Name: "经济舱"}, Price: 880, Rate: 0.7, RestSeats: 9},
+			{Cabin: flightgo.Cabin{Code: "F", Name: "头等舱"}, Price: 5600, Rate: 1},
+		},
+	}}}
+	// 国际航班的票价在行程上, 每个航段重复输出
+	international := flightgo.Itinerary{
+		Legs: []flightgo.Leg{
+			{FlightNumber: "KE856", DepartureTime: testDateTime(9, 40)},
+			{FlightNumber: "KE703", DepartureTime: testDateTime(14, 55), TransferDuration: 125},
+		},
+		Duration: 470,
+		Fares:    []flightgo.Fare{{Cabin: flightgo.Cabin{Code: "y_s", Name: "经济舱"}, Price: 1420, Tax: 610}},
+	}
+	noFare := flightgo.Itinerary{Legs: []flightgo.Leg{{FlightNumber: "MU5138"}}}
+	tests := []struct {
+		name        string
+		itineraries []flightgo.Itinerary
+		// 每行的航班号和票价列
+		rows [][2]string
+	}{
+		{"航段票价", []flightgo.Itinerary{domestic}, [][2]string{{"CA1501", "880"}, {"CA1501", "5600"}}},
+		{"行程票价", []flightgo.Itinerary{international}, [][2]string{{"KE856", "1420"}, {"KE703", "1420"}}},
+		{"没有票价", []flightgo.Itinerary{noFare}, [][2]string{{"MU5138", ""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeItineraries(&buf, OutputCSV, tt.itineraries); err != nil {
+				t.Fatalf("输出 CSV 失败: %v", err)
+			}
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("解析 CSV 失败: %v", err)
+			}
+			if len(records) != len(tt.rows)+1 || strings.Join(records[0], ",") != strings.Join(itineraryCSVHeader, ",") {
+				t.Fatalf("CSV: %v", records)
+			}
+			column := func(name string) int {
+				for i, header := range itineraryCSVHeader {
+					if header == name {
+						return i
+					}
+				}
+				t.Fatalf("缺少列 %s", name)
+				return -1
+			}
+			for i, want := range tt.rows {
+				row := records[i+1]
+				if len(row) != len(itineraryCSVHeader) {
+					t.Errorf("第 %d 行的列数: %d, 期望 %d", i+1, len(row), len(itineraryCSVHeader))
+					continue
+				}
+				if row[column("flight_number")] != want[0] || row[column("price")] != want[1] {
+					t.Errorf("第 %d 行: %s %s, 期望 %s %s", i+1, row[column("flight_number")], row[column("price")], want[0], want[1])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteItinerariesJSON(t *testing.T) {
+	itineraries := []flightgo.Itinerary{
+		{Legs: []flightgo.Leg{{FlightNumber: "CA1501", DepartureTime: testDateTime(8, 30)}}},
+		{Legs: []flightgo.Leg{{FlightNumber: "MU5138"}}},
+	}
+	var buf bytes.Buffer
+	if err := writeItineraries(&buf, OutputJSON, itineraries); err != nil {
+		t.Fatalf("输出 JSON 失败: %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 2 {
+		t.Fatalf("JSON: %s（%v）", buf.String(), err)
+	}
+	leg := decoded[0]["legs"].([]interface{})[0].(map[string]interface{})
+	if leg["departureTime"] != "2019-11-15T08:30:00+08:00" {
+		t.Errorf("起飞时间: %v", leg["departureTime"])
+	}
+	// 零值时间输出为 null
+	if leg := decoded[1]["legs"].([]interface{})[0].(map[string]interface{}); leg["departureTime"] != nil {
+		t.Errorf("零值起飞时间: %v, 期望 null", leg["departureTime"])
+	}
+
+	buf.Reset()
+	if err := writeItineraries(&buf, OutputNDJSON, itineraries); err != nil {
+		t.Fatalf("输出 NDJSON 失败: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "CA1501") || !strings.Contains(lines[1], "MU5138") {
+		t.Errorf("NDJSON: %q", lines)
+	}
+}
+
+func TestWriteBoardEntriesCSV(t *testing.T) {
+	entries := []flightgo.BoardEntry{{
+		Direction:     "dep",
+		FlightNumber:  "CZ3539",
+		StatusCode:    4,
+		Status:        "延误",
+		Phase:         flightgo.FlightPhaseDelayed,
+		Departure:     flightgo.Airport{CityName: "广州", Name: "广州白云"},
+		Arrival:       flightgo.Airport{CityName: "上海", Name: "上海虹桥"},
+		ScheduledTime: testDateTime(9, 0),
+	}}
+	var buf bytes.Buffer
+	if err := writeBoardEntries(&buf, OutputCSV, entries); err != nil {
+		t.Fatalf("输出 CSV 失败: %v", err)
+	}
+	want := strings.Join(boardEntryCSVHeader, ",") + "\n" +
+		"dep,CZ3539,,4,延误,delayed,广州,广州白云,上海,上海虹桥,2019-11-15T09:00:00+08:00,,\n"
+	if buf.String() != want {
+		t.Errorf("CSV:\n%s\n期望:\n%s", buf.String(), want)
+	}
+}
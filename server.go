@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+// HTTP 接口路径
+const (
+	apiDomesticPath      string = "/api/v1/domestic"
+	apiInternationalPath string = "/api/v1/international"
+	apiFlightStatusPath  string = "/api/v1/flight-status"
+	apiAirportBoardPath  string = "/api/v1/airport-board"
+	apiOpenAPIPath       string = "/openapi.json"
+	apiHealthPath        string = "/healthz"
+)
+
+// 默认的单次查询超时时间（国际航班需要多次拉取数据, 耗时较长）
+const defaultServeTimeout = 60 * time.Second
+
+// 错误类型对应的 HTTP 状态码和错误代码
+var errorKindHTTPStatus = map[flightgo.ErrorKind]int{
+	flightgo.ErrorNetwork:         http.StatusBadGateway,
+	flightgo.ErrorUpstreamEmpty:   http.StatusBadGateway,
+	flightgo.ErrorParse:           http.StatusBadGateway,
+	flightgo.ErrorInvalidArgument: http.StatusBadRequest,
+	flightgo.ErrorUnknownCity:     http.StatusNotFound,
+	flightgo.ErrorAntiBotBlocked:  http.StatusServiceUnavailable,
+}
+
+var errorKindCodes = map[flightgo.ErrorKind]string{
+	flightgo.ErrorNetwork:         "network",
+	flightgo.ErrorUpstreamEmpty:   "upstream_empty",
+	flightgo.ErrorParse:           "parse",
+	flightgo.ErrorInvalidArgument: "invalid_argument",
+	flightgo.ErrorUnknownCity:     "unknown_city",
+	flightgo.ErrorAntiBotBlocked:  "anti_bot_blocked",
+}
+
+// 接口错误响应
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// HTTP 接口服务（所有接口共用一个查询客户端）
+type APIServer struct {
+	client  *flightgo.Client
+	timeout time.Duration
+	mux     *http.ServeMux
+}
+
+func NewAPIServer(client *flightgo.Client, timeout time.Duration) *APIServer {
+	if timeout <= 0 {
+		timeout = defaultServeTimeout
+	}
+	s := &APIServer{client: client, timeout: timeout, mux: http.NewServeMux()}
+	s.mux.HandleFunc(apiDomesticPath, s.handleDomestic)
+	s.mux.HandleFunc(apiInternationalPath, s.handleInternational)
+	s.mux.HandleFunc(apiFlightStatusPath, s.handleFlightStatus)
+	s.mux.HandleFunc(apiAirportBoardPath, s.handleAirportBoard)
+	s.mux.HandleFunc(apiOpenAPIPath, s.handleOpenAPI)
+	s.mux.HandleFunc(apiHealthPath, s.handleHealth)
+	return s
+}
+
+// 记录访问日志的 ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (s *APIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		recorder.Header().Set("Allow", "GET, HEAD")
+		writeAPIError(recorder, http.StatusMethodNotAllowed, "method_not_allowed", "只支持 GET 请求")
+	} else {
+		s.mux.ServeHTTP(recorder, r)
+	}
+	logger.Infof("[Flight-Go]%s %s %d %s", r.Method, r.URL.RequestURI(), recorder.status, time.Since(start).Round(time.Millisecond))
+}
+
+// 单次查询的 context（客户端断开或超时后取消查询）
+func (s *APIServer) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), s.timeout)
+}
+
+// 查询国内航班
+func (s *APIServer) handleDomestic(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if err := requireQueryParams(query, "departure", "arrival", "date"); err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	filter, err := parseItineraryQueryFilter(query)
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	order, err := parseItineraryQuerySort(query)
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+	departure, arrival, date := query.Get("departure"), query.Get("arrival"), query.Get("date")
+	itineraries, err := s.client.SearchDomestic(ctx, flightgo.DomesticSearchRequest{
+		Departure: departure,
+		Arrival:   arrival,
+		Date:      date,
+		TripType:  flightgo.TripTypeOneway,
+	})
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	recordFareHistory(MarketDomestic, departure, arrival, date, itineraries)
+	writeAPIJSON(w, http.StatusOK, order.Apply(filter.Apply(itineraries)))
+}
+
+// 查询国际航班
+func (s *APIServer) handleInternational(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if err := requireQueryParams(query, "departure", "arrival", "date"); err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	filter, err := parseItineraryQueryFilter(query)
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	order, err := parseItineraryQuerySort(query)
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+	departure, arrival, date := query.Get("departure"), query.Get("arrival"), query.Get("date")
+	itineraries, err := s.client.SearchInternational(ctx, flightgo.InternationalSearchRequest{
+		Departure: departure,
+		Arrival:   arrival,
+		Date:      date,
+		Cabin:     query.Get("cabin"),
+		// 在接口超时前停止轮询, 返回已加载的结果
+		Timeout: s.timeout - s.timeout/10,
+	})
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	recordFareHistory(MarketInternational, departure, arrival, date, itineraries)
+	// 国际航班按查询的舱位等级报价, cabin 参数不再用于过滤票价
+	filter.CabinName = ""
+	writeAPIJSON(w, http.StatusOK, order.Apply(filter.Apply(itineraries)))
+}
+
+// 查询航班动态
+func (s *APIServer) handleFlightStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if err := requireQueryParams(query, "flightNumber", "date"); err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+	statuses, err := s.client.FlightStatus(ctx, query.Get("flightNumber"), strings.Replace(query.Get("date"), "-", "", -1))
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, statuses)
+}
+
+// 查询机场进出港信息
+func (s *APIServer) handleAirportBoard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if err := requireQueryParams(query, "airport"); err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	direction := query.Get("direction")
+	if direction == "" {
+		direction = flightgo.DirectionDeparture
+	}
+	var next time.Duration
+	if value := query.Get("next"); value != "" {
+		minutes, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			writeAPIFlightError(w, flightgo.NewInvalidArgumentError("next 必须是整数（分钟）: %s", value))
+			return
+		}
+		next = time.Duration(minutes) * time.Minute
+	}
+	since, until, err := airportBoardTimeRange(query.Get("date"), query.Get("window"), next, time.Now())
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+	entries, err := s.client.AirportBoard(ctx, flightgo.AirportBoardRequest{
+		Airport:   query.Get("airport"),
+		Direction: direction,
+		Date:      query.Get("date"),
+		Since:     since,
+		Until:     until,
+	})
+	if err != nil {
+		writeAPIFlightError(w, err)
+		return
+	}
+	recordBoardHistory(entries)
+	filter := flightgo.BoardFilter{
+		Airlines:     splitFlagValues(query.Get("airline")),
+		FlightNumber: query.Get("flightNumber"),
+		City:         query.Get("city"),
+		Statuses:     splitFlagValues(query.Get("status")),
+		Aircraft:     query.Get("aircraft"),
+	}
+	writeAPIJSON(w, http.StatusOK, filter.Apply(entries))
+}
+
+// OpenAPI 接口描述
+func (s *APIServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write([]byte(openAPISpec))
+}
+
+// 健康检查
+func (s *APIServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeAPIJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": currentServiceVersion})
+}
+
+// 检查必填的查询参数
+func requireQueryParams(query url.Values, names ...string) error {
+	missing := make([]string, 0)
+	for _, name := range names {
+		if query.Get(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return flightgo.NewInvalidArgumentError("缺少查询参数: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// 解析航班过滤条件（查询参数: airline, flightNumber, departWindow, arriveWindow, aircraft, meal, minPunctuality,
+// cabin, maxPrice, maxStops, maxLayover）
+func parseItineraryQueryFilter(query url.Values) (flightgo.ItineraryFilter, error) {
+	filter := flightgo.ItineraryFilter{
+		FlightNumber: query.Get("flightNumber"),
+		Aircraft:     query.Get("aircraft"),
+		CabinName:    query.Get("cabin"),
+		MaxStops:     -1,
+	}
+	for _, airline := range query["airline"] {
+		filter.Airlines = append(filter.Airlines, strings.Split(airline, ",")...)
+	}
+	var err error
+	if value := query.Get("departWindow"); value != "" {
+		if filter.DepartureWindow, err = flightgo.ParseClockWindow(value); err != nil {
+			return filter, err
+		}
+	}
+	if value := query.Get("arriveWindow"); value != "" {
+		if filter.ArrivalWindow, err = flightgo.ParseClockWindow(value); err != nil {
+			return filter, err
+		}
+	}
+	if value := query.Get("meal"); value != "" {
+		if filter.RequireMeal, err = strconv.ParseBool(value); err != nil {
+			return filter, flightgo.NewInvalidArgumentError("meal 参数格式错误: %s", value)
+		}
+	}
+	if value := query.Get("minPunctuality"); value != "" {
+		if filter.MinPunctuality, err = strconv.ParseFloat(value, 64); err != nil || filter.MinPunctuality < 0 {
+			return filter, flightgo.NewInvalidArgumentError("minPunctuality 参数格式错误: %s", value)
+		}
+	}
+	if value := query.Get("maxPrice"); value != "" {
+		if filter.MaxPrice, err = strconv.ParseInt(value, 10, 64); err != nil || filter.MaxPrice < 0 {
+			return filter, flightgo.NewInvalidArgumentError("maxPrice 参数格式错误: %s", value)
+		}
+	}
+	if value := query.Get("maxStops"); value != "" {
+		if filter.MaxStops, err = strconv.Atoi(value); err != nil || filter.MaxStops < 0 {
+			return filter, flightgo.NewInvalidArgumentError("maxStops 参数格式错误: %s", value)
+		}
+	}
+	if value := query.Get("maxLayover"); value != "" {
+		if filter.MaxLayover, err = strconv.ParseInt(value, 10, 64); err != nil || filter.MaxLayover < 0 {
+			return filter, flightgo.NewInvalidArgumentError("maxLayover 参数格式错误（单位: 分钟）: %s", value)
+		}
+	}
+	filter.DirectOnly = filter.MaxStops == 0
+	if filter.MaxStops < 0 {
+		filter.MaxStops = 0
+	}
+	return filter, nil
+}
+
+// 解析排序方式（查询参数 sort, 格式: 字段[:asc|desc]）
+func parseItineraryQuerySort(query url.Values) (flightgo.ItinerarySort, error) {
+	if query.Get("sort") == "" {
+		return flightgo.ItinerarySort{}, nil
+	}
+	return flightgo.ParseItinerarySort(query.Get("sort"))
+}
+
+// 输出 JSON 响应
+func writeAPIJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		logger.Warnf("[Flight-Go]输出接口响应失败, 错误原因: %v", err)
+	}
+}
+
+// 输出错误响应
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	writeAPIJSON(w, status, apiErrorResponse{Error: APIError{Code: code, Message: message}})
+}
+
+// 按错误类型输出错误响应（超时返回 504, 未分类的错误返回 500）
+func writeAPIFlightError(w http.ResponseWriter, err error) {
+	logger.Debugf("[Flight-Go]%+v", err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		writeAPIError(w, http.StatusGatewayTimeout, "timeout", err.Error())
+		return
+	}
+	var flightErr *flightgo.Error
+	if errors.As(err, &flightErr) {
+		if status, ok := errorKindHTTPStatus[flightErr.Kind]; ok {
+			writeAPIError(w, status, errorKindCodes[flightErr.Kind], err.Error())
+			return
+		}
+	}
+	writeAPIError(w, http.StatusInternalServerError, "unknown", err.Error())
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+	"github.com/tidwall/gjson"
+)
+
+// 读取 testdata/cassettes 中录制的接口响应
+func readCassetteBody(t *testing.T, name string) string {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "cassettes", name))
+	if err != nil {
+		t.Fatalf("读取 %s 失败: %v", name, err)
+	}
+	return gjson.GetBytes(data, "response.body").String()
+}
+
+// 上游接口返回录制的国内航班和航班动态数据的接口服务
+func newTestAPIServer(t *testing.T) *APIServer {
+	t.Helper()
+	t.Setenv(historyDirEnv, t.TempDir())
+	products := readCassetteBody(t, "schedule/db7cc40becea0ca6-001.json")
+	statuses := readCassetteBody(t, "code/02c875756c8e35e7-001.json")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/itinerary/api/12808/products", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(products))
+	})
+	mux.HandleFunc("/international/search/api/poi/search", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Data":[]}`))
+	})
+	mux.HandleFunc("/adsb/index/advancedSearch", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("searchDate") != "20191115" {
+			t.Errorf("航班动态的日期参数: %s", r.FormValue("searchDate"))
+		}
+		_, _ = w.Write([]byte(statuses))
+	})
+	mux.HandleFunc("/adsb/airport/api/departures", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	upstream := httptest.NewServer(mux)
+	t.Cleanup(upstream.Close)
+	client := flightgo.New(
+		flightgo.WithHTTPClient(upstream.Client()),
+		flightgo.WithEndpoints(flightgo.Endpoints{CtripBaseURL: upstream.URL, VariFlightBaseURL: upstream.URL}),
+	)
+	return NewAPIServer(client, 0)
+}
+
+func TestAPIServer(t *testing.T) {
+	server := newTestAPIServer(t)
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+		// 期望的结果数量或错误代码
+		count int
+		code  string
+	}{
+		{"国内航班", http.MethodGet, "/api/v1/domestic?departure=北京&arrival=上海&date=2019-11-15", http.StatusOK, 2, ""},
+		{"按航空公司过滤", http.MethodGet, "/api/v1/domestic?departure=北京&arrival=上海&date=2019-11-15&airline=MU", http.StatusOK, 1, ""},
+		{"按价格过滤", http.MethodGet, "/api/v1/domestic?departure=北京&arrival=上海&date=2019-11-15&cabin=经济舱&maxPrice=700", http.StatusOK, 1, ""},
+		{"价格参数错误", http.MethodGet, "/api/v1/domestic?departure=北京&arrival=上海&date=2019-11-15&maxPrice=abc", http.StatusBadRequest, 0, "invalid_argument"},
+		{"缺少参数", http.MethodGet, "/api/v1/domestic?departure=北京", http.StatusBadRequest, 0, "invalid_argument"},
+		{"未知的城市", http.MethodGet, "/api/v1/domestic?departure=不存在的城市&arrival=上海&date=2019-11-15", http.StatusNotFound, 0, "unknown_city"},
+		{"航班动态", http.MethodGet, "/api/v1/flight-status?flightNumber=CA1501&date=2019-11-15", http.StatusOK, 2, ""},
+		{"被拦截", http.MethodGet, "/api/v1/airport-board?airport=广州", http.StatusServiceUnavailable, 0, "anti_bot_blocked"},
+		{"不支持的请求方法", http.MethodPost, "/api/v1/domestic", http.StatusMethodNotAllowed, 0, "method_not_allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.target, nil))
+			if recorder.Code != tt.status {
+				t.Fatalf("状态码: %d, 期望 %d（%s）", recorder.Code, tt.status, recorder.Body.String())
+			}
+			if tt.code != "" {
+				var response apiErrorResponse
+				if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Error.Code != tt.code {
+					t.Errorf("错误响应: %s, 期望错误代码 %s", recorder.Body.String(), tt.code)
+				}
+				return
+			}
+			var results []json.RawMessage
+			if err := json.Unmarshal(recorder.Body.Bytes(), &results); err != nil || len(results) != tt.count {
+				t.Errorf("结果: %s, 期望 %d 条", recorder.Body.String(), tt.count)
+			}
+		})
+	}
+}
+
+func TestParseItineraryQueryFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		check func(flightgo.ItineraryFilter) bool
+		valid bool
+	}{
+		{"", func(f flightgo.ItineraryFilter) bool { return !f.DirectOnly && f.MaxStops == 0 }, true},
+		{"airline=MU,CA&airline=HU", func(f flightgo.ItineraryFilter) bool { return len(f.Airlines) == 3 && f.Airlines[2] == "HU" }, true},
+		{"departWindow=08:00-12:00&meal=true", func(f flightgo.ItineraryFilter) bool {
+			return f.DepartureWindow.String() == "08:00-12:00" && f.RequireMeal
+		}, true},
+		// maxStops=0 表示只显示直飞
+		{"maxStops=0", func(f flightgo.ItineraryFilter) bool { return f.DirectOnly && f.MaxStops == 0 }, true},
+		{"maxStops=2&maxLayover=180", func(f flightgo.ItineraryFilter) bool { return !f.DirectOnly && f.MaxStops == 2 && f.MaxLayover == 180 }, true},
+		{"departWindow=8点", nil, false},
+		{"meal=yes", nil, false},
+		{"minPunctuality=-1", nil, false},
+		{"maxPrice=abc", nil, false},
+		{"maxStops=-1", nil, false},
+		{"maxLayover=2h", nil, false},
+	}
+	for _, tt := range tests {
+		query, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter, err := parseItineraryQueryFilter(query)
+		if (err == nil) != tt.valid {
+			t.Errorf("parseItineraryQueryFilter(%q): %v, 期望合法: %v", tt.query, err, tt.valid)
+			continue
+		}
+		if tt.valid && !tt.check(filter) {
+			t.Errorf("parseItineraryQueryFilter(%q) = %+v", tt.query, filter)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+const (
+	statusLogFileName string = "unknown_status_codes.ndjson"
+	statusLogEnv      string = "FLIGHT_GO_STATUS_LOG"
+)
+
+// 状态码映射的来源
+const (
+	StatusCodeSourceBuiltin string = "builtin"
+	StatusCodeSourceConfig  string = "config"
+	StatusCodeSourceUnknown string = "unknown"
+)
+
+var statusCodeSourceNames = map[string]string{
+	StatusCodeSourceBuiltin: "内置",
+	StatusCodeSourceConfig:  "配置文件",
+	StatusCodeSourceUnknown: "未知",
+}
+
+// 默认的未知状态码日志路径（依次取 FLIGHT_GO_STATUS_LOG 环境变量、配置文件中的 statusLogPath 和用户配置目录下的 flight-go/unknown_status_codes.ndjson）
+func defaultStatusLogPath() string {
+	if path := os.Getenv(statusLogEnv); path != "" {
+		return path
+	}
+	if config.StatusLogPath != "" {
+		return config.StatusLogPath
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return statusLogFileName
+	}
+	return filepath.Join(configDir, "flight-go", statusLogFileName)
+}
+
+// 解析配置文件中补充的状态码映射（键为状态码, 阶段可以是英文标识或中文名称）
+func parseConfigFlightStatusCodes(codes map[string]flightgo.FlightStatusCode) (map[int64]flightgo.FlightStatusCode, error) {
+	parsed := make(map[int64]flightgo.FlightStatusCode, len(codes))
+	for key, status := range codes {
+		code, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("flightStatusCodes 中的状态码必须是整数: %s", key)
+		}
+		if status.Name == "" {
+			return nil, fmt.Errorf("flightStatusCodes 中状态码 %d 缺少 name", code)
+		}
+		if status.Phase != "" {
+			if status.Phase, err = flightgo.ParseFlightPhase(string(status.Phase)); err != nil {
+				return nil, fmt.Errorf("flightStatusCodes 中状态码 %d 的阶段错误: %v", code, err)
+			}
+		}
+		parsed[code] = status
+	}
+	return parsed, nil
+}
+
+// 本次运行中已经记录过的未知状态码（同一航段的同一状态码只记录一次, 避免跟踪航班时重复记录）
+var (
+	unknownStatusMu     sync.Mutex
+	unknownStatusLogged = make(map[string]bool)
+)
+
+// 记录未知状态码到本地日志（失败时只记录日志; 回放时不记录）
+func recordUnknownFlightStatus(unknown flightgo.UnknownFlightStatus) {
+	if cassetteReplayDir != "" {
+		return
+	}
+	key := fmt.Sprintf("%d|%s|%s|%s", unknown.Code, unknown.FlightNumber, unknown.Departure, unknown.Arrival)
+	unknownStatusMu.Lock()
+	defer unknownStatusMu.Unlock()
+	if unknownStatusLogged[key] {
+		return
+	}
+	unknownStatusLogged[key] = true
+	if err := appendUnknownFlightStatus(defaultStatusLogPath(), unknown); err != nil {
+		logger.Warnf("[Flight-Go]记录未知状态码失败, 错误原因: %v", err)
+	}
+}
+
+// 追加一条未知状态码记录（每行一条 JSON 记录）
+func appendUnknownFlightStatus(path string, unknown flightgo.UnknownFlightStatus) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	data, err := json.Marshal(unknown)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// 读取未知状态码日志（文件不存在时返回空）
+func readUnknownFlightStatuses(path string) ([]flightgo.UnknownFlightStatus, error) {
+	unknowns := make([]flightgo.UnknownFlightStatus, 0)
+	err := scanHistoryFile(path, func(line []byte) error {
+		var unknown flightgo.UnknownFlightStatus
+		if err := json.Unmarshal(line, &unknown); err == nil {
+			unknowns = append(unknowns, unknown)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return unknowns, nil
+}
+
+// 状态码映射中的一条
+type FlightStatusCodeEntry struct {
+	Code  int64                `json:"code"`
+	Name  string               `json:"name"`
+	Phase flightgo.FlightPhase `json:"phase"`
+	// 来源（builtin: 内置; config: 配置文件; unknown: 只在未知状态码日志中出现过）
+	Source string `json:"source"`
+	// 未知状态码日志中的记录次数和最近一次记录
+	Observations     int        `json:"observations"`
+	LastObservedAt   *time.Time `json:"lastObservedAt,omitempty"`
+	LastFlightNumber string     `json:"lastFlightNumber,omitempty"`
+}
+
+// 可以配置状态码映射的阶段（按展示顺序, 不包括 unknown）
+var mappableFlightPhases = []flightgo.FlightPhase{
+	flightgo.FlightPhaseScheduled, flightgo.FlightPhaseBoarding, flightgo.FlightPhaseDeparted, flightgo.FlightPhaseInAir,
+	flightgo.FlightPhaseLanded, flightgo.FlightPhaseDelayed, flightgo.FlightPhaseDiverted, flightgo.FlightPhaseReturned,
+	flightgo.FlightPhaseCancelled,
+}
+
+// 内置映射和配置文件中都没有状态码的阶段（数据源返回的状态不会映射到这些阶段）
+func unmappedFlightPhases(entries []FlightStatusCodeEntry) []flightgo.FlightPhase {
+	mapped := make(map[flightgo.FlightPhase]bool)
+	for _, entry := range entries {
+		if entry.Source != StatusCodeSourceUnknown {
+			mapped[entry.Phase] = true
+		}
+	}
+	unmapped := make([]flightgo.FlightPhase, 0)
+	for _, phase := range mappableFlightPhases {
+		if !mapped[phase] {
+			unmapped = append(unmapped, phase)
+		}
+	}
+	return unmapped
+}
+
+// 合并内置映射、配置文件中的映射和未知状态码日志（按状态码排序）
+func flightStatusCodeEntries(configCodes map[int64]flightgo.FlightStatusCode, unknowns []flightgo.UnknownFlightStatus) []FlightStatusCodeEntry {
+	entries := make(map[int64]*FlightStatusCodeEntry)
+	for code, status := range flightgo.FlightStatusCodes {
+		entries[code] = &FlightStatusCodeEntry{Code: code, Name: status.Name, Phase: status.Phase, Source: StatusCodeSourceBuiltin}
+	}
+	for code, status := range configCodes {
+		phase := status.Phase
+		if phase == "" {
+			phase = flightgo.FlightPhaseOfStatusName(status.Name)
+		}
+		entries[code] = &FlightStatusCodeEntry{Code: code, Name: status.Name, Phase: phase, Source: StatusCodeSourceConfig}
+	}
+	for _, unknown := range unknowns {
+		entry, ok := entries[unknown.Code]
+		if !ok {
+			entry = &FlightStatusCodeEntry{
+				Code:   unknown.Code,
+				Name:   flightgo.UnknownFlightStatusName(unknown.Code),
+				Phase:  flightgo.FlightPhaseUnknown,
+				Source: StatusCodeSourceUnknown,
+			}
+			entries[unknown.Code] = entry
+		}
+		entry.Observations++
+		if entry.LastObservedAt == nil || !unknown.ObservedAt.Before(*entry.LastObservedAt) {
+			observedAt := unknown.ObservedAt
+			entry.LastObservedAt = &observedAt
+			entry.LastFlightNumber = unknown.FlightNumber
+		}
+	}
+	sorted := make([]FlightStatusCodeEntry, 0, len(entries))
+	for _, entry := range entries {
+		sorted = append(sorted, *entry)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Code < sorted[j].Code
+	})
+	return sorted
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+func TestParseConfigFlightStatusCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		codes map[string]flightgo.FlightStatusCode
+		valid bool
+	}{
+		{"英文阶段", map[string]flightgo.FlightStatusCode{"3": {Name: "备降", Phase: "diverted"}}, true},
+		{"中文阶段", map[string]flightgo.FlightStatusCode{"5": {Name: "返航", Phase: "返航"}}, true},
+		{"省略阶段", map[string]flightgo.FlightStatusCode{"6": {Name: "登机"}}, true},
+		{"状态码不是整数", map[string]flightgo.FlightStatusCode{"x": {Name: "备降"}}, false},
+		{"缺少名称", map[string]flightgo.FlightStatusCode{"3": {Phase: "diverted"}}, false},
+		{"未知阶段", map[string]flightgo.FlightStatusCode{"3": {Name: "备降", Phase: "landing"}}, false},
+	}
+	for _, tt := range tests {
+		codes, err := parseConfigFlightStatusCodes(tt.codes)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: %v, 期望合法: %v", tt.name, err, tt.valid)
+		}
+		if tt.valid && len(codes) != 1 {
+			t.Errorf("%s: %+v", tt.name, codes)
+		}
+	}
+	codes, _ := parseConfigFlightStatusCodes(map[string]flightgo.FlightStatusCode{"5": {Name: "返航", Phase: "返航"}})
+	if codes[5].Phase != flightgo.FlightPhaseReturned {
+		t.Errorf("阶段: %s, 期望 %s", codes[5].Phase, flightgo.FlightPhaseReturned)
+	}
+}
+
+func TestFlightStatusCodeEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flight-go", statusLogFileName)
+	observedAt := time.Date(2019, 11, 15, 9, 0, 0, 0, time.UTC)
+	for i, unknown := range []flightgo.UnknownFlightStatus{
+		{ObservedAt: observedAt, Code: 3, FlightNumber: "CA1501"},
+		{ObservedAt: observedAt.Add(time.Hour), Code: 3, FlightNumber: "MU5138"},
+		{ObservedAt: observedAt, Code: 9, FlightNumber: "CZ3101"},
+	} {
+		if err := appendUnknownFlightStatus(path, unknown); err != nil {
+			t.Fatalf("第 %d 条记录写入失败: %v", i+1, err)
+		}
+	}
+	unknowns, err := readUnknownFlightStatuses(path)
+	if err != nil || len(unknowns) != 3 {
+		t.Fatalf("读取未知状态码: %+v, %v", unknowns, err)
+	}
+	entries := flightStatusCodeEntries(map[int64]flightgo.FlightStatusCode{3: {Name: "备降"}}, unknowns)
+	byCode := make(map[int64]FlightStatusCodeEntry)
+	for i, entry := range entries {
+		if i > 0 && entries[i-1].Code >= entry.Code {
+			t.Errorf("没有按状态码排序: %d %d", entries[i-1].Code, entry.Code)
+		}
+		byCode[entry.Code] = entry
+	}
+	tests := []struct {
+		code         int64
+		source       string
+		phase        flightgo.FlightPhase
+		observations int
+		lastFlight   string
+	}{
+		{flightgo.FlightStatusArrived, StatusCodeSourceBuiltin, flightgo.FlightPhaseLanded, 0, ""},
+		{3, StatusCodeSourceConfig, flightgo.FlightPhaseDiverted, 2, "MU5138"},
+		{9, StatusCodeSourceUnknown, flightgo.FlightPhaseUnknown, 1, "CZ3101"},
+	}
+	for _, tt := range tests {
+		entry, ok := byCode[tt.code]
+		if !ok || entry.Source != tt.source || entry.Phase != tt.phase || entry.Observations != tt.observations || entry.LastFlightNumber != tt.lastFlight {
+			t.Errorf("状态码 %d: %+v", tt.code, entry)
+		}
+	}
+	// 内置映射没有的阶段中, 配置文件补充了备降, 日志中的未知状态码不算映射
+	unmapped := make([]string, 0)
+	for _, phase := range unmappedFlightPhases(entries) {
+		unmapped = append(unmapped, string(phase))
+	}
+	if got := strings.Join(unmapped, " "); got != "boarding in-air returned" {
+		t.Errorf("没有状态码映射的阶段: %s", got)
+	}
+	// 日志文件不存在时返回空
+	if unknowns, err := readUnknownFlightStatuses(filepath.Join(t.TempDir(), "missing.ndjson")); err != nil || len(unknowns) != 0 {
+		t.Errorf("读取不存在的日志: %+v, %v", unknowns, err)
+	}
+}
@@ -0,0 +1,471 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liyu4/tablewriter"
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+// 新建表格
+func newResultTable(header []string) *tablewriter.Table {
+	table := tablewriter.NewColorWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader(header)
+	return table
+}
+
+// 机型展示名称
+func aircraftDisplayName(leg flightgo.Leg) string {
+	// TODO 暂时替换(嫌他太长了) 貌似原数据的是 <全新 A350-900>
+	aircraftTypeName := strings.Replace(leg.AircraftName, "全新", "", -1)
+	aircraftTypeName = strings.Replace(aircraftTypeName, " ", "", -1)
+	aircraftTypeName = strings.Replace(aircraftTypeName, "A350-900", "350", -1)
+	return fmt.Sprintf("%s(%s)", aircraftTypeName, leg.AircraftCode)
+}
+
+// 票价展示
+func fareDisplayString(fare flightgo.Fare) string {
+	// 折扣信息
+	var rates string
+	if fare.Rate == 1.0 {
+		rates = "无折扣"
+	} else {
+		rates = fmt.Sprintf("%.1f折", fare.Rate*10)
+	}
+	return fmt.Sprintf("价格:%d元（%s,剩余:%d张）", fare.Price, rates, fare.RestSeats)
+}
+
+// 按舱位等级分组的票价展示（票价已按价格升序排列, 价格相同的票价分别展示; 其他舱位等级的票价前加上舱位名称）
+func cabinFareStrings(fares []flightgo.Fare) (economyClassPrices, businessClassPrices, firstClassPrices, otherClassPrices []string) {
+	economyClassPrices = make([]string, 0)
+	businessClassPrices = make([]string, 0)
+	firstClassPrices = make([]string, 0)
+	otherClassPrices = make([]string, 0)
+	for _, fare := range fares {
+		switch fare.Cabin.Name {
+		case flightgo.EconomyClassName:
+			economyClassPrices = append(economyClassPrices, fareDisplayString(fare))
+		case flightgo.BusinessClassName:
+			businessClassPrices = append(businessClassPrices, fareDisplayString(fare))
+		case flightgo.FirstClassName:
+			firstClassPrices = append(firstClassPrices, fareDisplayString(fare))
+		default:
+			cabinName := fare.Cabin.Name
+			if cabinName == "" {
+				cabinName = fare.Cabin.Code
+			}
+			otherClassPrices = append(otherClassPrices, fmt.Sprintf("%s %s", cabinName, fareDisplayString(fare)))
+		}
+	}
+	return economyClassPrices, businessClassPrices, firstClassPrices, otherClassPrices
+}
+
+// 单元格中的票价（onlyLowPrice 时只展示最低价, 否则每行展示一个票价）
+func fareCellString(prices []string, onlyLowPrice bool) string {
+	if len(prices) == 0 {
+		return "无"
+	}
+	if onlyLowPrice {
+		return prices[0]
+	}
+	return strings.Join(prices, "\n")
+}
+
+// 渲染国内航班表格
+// onlyLowPrice 为 false 时列出每个舱位等级的全部票价, 并增加其他舱位等级（例如超级经济舱）一列
+func renderMainLandFlightTable(itineraries []flightgo.Itinerary, onlyLowPrice bool) {
+	header := FlightTableHeader
+	if !onlyLowPrice {
+		header = append(append([]string{}, FlightTableHeader...), FlightTableOtherCabinHeader)
+	}
+	table := newResultTable(header)
+	if !onlyLowPrice {
+		table.SetRowLine(true)
+		table.SetAutoWrapText(false)
+	}
+	for _, itinerary := range itineraries {
+		for _, leg := range itinerary.Legs {
+			departureInfo := fmt.Sprintf(DepartureStrFormat, leg.Departure.CityName, leg.Departure.Name, leg.Departure.Terminal)
+			arrivalInfo := fmt.Sprintf(ArrivalStrFormat, leg.Arrival.CityName, leg.Arrival.Name, leg.Arrival.Terminal)
+			// 餐食
+			var mealInfo = HasNotMeal
+			if leg.HasMeal {
+				mealInfo = HasMeal
+			}
+			// 航班价格
+			economyClassPrices, businessClassPrices, firstClassPrices, otherClassPrices := cabinFareStrings(leg.Fares)
+			// 合并到表格中
+			row := []string{
+				leg.AirlineName, leg.FlightNumber, departureInfo, leg.DepartureTime.Format("15:04"), arrivalInfo, leg.ArrivalTime.Format("15:04"),
+				aircraftDisplayName(leg), mealInfo, leg.PunctualityRate,
+				fareCellString(economyClassPrices, onlyLowPrice),
+				fareCellString(businessClassPrices, onlyLowPrice),
+				fareCellString(firstClassPrices, onlyLowPrice),
+			}
+			if !onlyLowPrice {
+				row = append(row, fareCellString(otherClassPrices, false))
+			}
+			table.Append(row)
+		}
+	}
+	table.Render()
+}
+
+// 多段行程中每一段的名称
+func tripSegmentTitle(trip *flightgo.Trip, index int) string {
+	segment := trip.Segments[index]
+	name := fmt.Sprintf("第 %d 段", index+1)
+	if trip.TripType == flightgo.TripTypeRoundTrip {
+		name = "去程"
+		if index == 1 {
+			name = "返程"
+		}
+	}
+	return fmt.Sprintf("%s: %s → %s %s", name, segment.Departure, segment.Arrival, segment.Date)
+}
+
+// 渲染多段行程（每段一张航班表格, 最后是各舱位等级的最低价组合）
+func renderTripTable(trip *flightgo.Trip, onlyLowPrice bool) {
+	for i, segment := range trip.Segments {
+		fmt.Println(tripSegmentTitle(trip, i))
+		renderMainLandFlightTable(segment.Itineraries, onlyLowPrice)
+	}
+	if len(trip.Cheapest) == 0 {
+		fmt.Println("没有可以组合的航班")
+		return
+	}
+	fmt.Println("最低组合价格:")
+	table := newResultTable(TripCombinationTableHeader)
+	for _, combination := range trip.Cheapest {
+		prices := make([]string, 0, len(combination.Fares))
+		for _, fare := range combination.Fares {
+			prices = append(prices, fmt.Sprintf("%d元", fare.TotalPrice()))
+		}
+		table.Append([]string{
+			combination.CabinName,
+			strings.Join(combination.FlightNumbers, " + "),
+			strings.Join(prices, " + "),
+			fmt.Sprintf("%d元", combination.TotalPrice),
+		})
+	}
+	table.Render()
+}
+
+// 价格日历单元格中的价格
+func calendarPriceString(cabinName string, price int64) string {
+	if price == 0 {
+		return fmt.Sprintf("%s: -", cabinName)
+	}
+	return fmt.Sprintf("%s: %d元", cabinName, price)
+}
+
+// 渲染价格日历（按周排列, 经济舱最低价格的日期会标记出来）
+func renderFareCalendarTable(days []flightgo.FareCalendarDay) {
+	var lowestEconomy int64
+	for _, day := range days {
+		if day.Economy > 0 && (lowestEconomy == 0 || day.Economy < lowestEconomy) {
+			lowestEconomy = day.Economy
+		}
+	}
+	table := newResultTable(FareCalendarTableHeader)
+	table.SetRowLine(true)
+	table.SetAutoWrapText(false)
+	row := make([]string, 7)
+	for i, day := range days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		// 周一为第一列
+		column := (int(date.Weekday()) + 6) % 7
+		if i > 0 && column == 0 {
+			table.Append(row)
+			row = make([]string, 7)
+		}
+		title := date.Format("01-02")
+		if day.Economy > 0 && day.Economy == lowestEconomy {
+			title += " (最低)"
+		}
+		if day.Error != "" {
+			row[column] = fmt.Sprintf("%s\n查询失败", title)
+			continue
+		}
+		row[column] = strings.Join([]string{
+			title,
+			calendarPriceString("经", day.Economy),
+			calendarPriceString("商", day.Business),
+			calendarPriceString("头", day.First),
+		}, "\n")
+	}
+	if len(days) > 0 {
+		table.Append(row)
+	}
+	table.Render()
+}
+
+// 渲染价格历史
+func renderFareHistoryTable(observations []FareObservation) {
+	table := newResultTable(FareHistoryTableHeader)
+	for _, observation := range observations {
+		rate := ""
+		if observation.Rate > 0 {
+			rate = fmt.Sprintf("%.1f折", observation.Rate*10)
+		}
+		table.Append([]string{
+			observation.ObservedAt.In(flightgo.ChinaLocation).Format("2006-01-02 15:04"),
+			fmt.Sprintf("%s-%s", observation.Departure, observation.Arrival),
+			observation.Date,
+			observation.FlightNumber,
+			observation.AirlineName,
+			timeToString(observation.DepartureTime),
+			observation.CabinName,
+			fmt.Sprintf("%d元", observation.TotalPrice()),
+			rate,
+			strconv.FormatInt(observation.RestSeats, 10),
+		})
+	}
+	table.Render()
+}
+
+// 渲染价格历史统计
+func renderFareHistorySummaryTable(summaries []FareHistorySummary) {
+	table := newResultTable(FareHistorySummaryTableHeader)
+	for _, summary := range summaries {
+		route := fmt.Sprintf("%s-%s", summary.Departure, summary.Arrival)
+		if summary.Market == MarketInternational {
+			route += "（国际）"
+		}
+		table.Append([]string{
+			route,
+			summary.Date,
+			summary.CabinName,
+			strconv.Itoa(summary.Observations),
+			fmt.Sprintf("%d元", summary.Lowest),
+			fmt.Sprintf("%d元", summary.Highest),
+			fmt.Sprintf("%d元", summary.Average),
+			fmt.Sprintf("%d元", summary.Latest),
+			fmt.Sprintf("%.0f%%", summary.LatestPercentile),
+		})
+	}
+	table.Render()
+}
+
+// 分钟转 x 小时 x 分钟
+func durationDisplayString(minutes int64) string {
+	hour, minute := minutesToHour(minutes)
+	return fmt.Sprintf("%d 小时 %d 分钟", hour, minute)
+}
+
+// 渲染国外航班表格（每个行程一张表格）
+func renderOverSeaFlightTable(itineraries []flightgo.Itinerary, cabinName string) {
+	for _, itinerary := range itineraries {
+		table := newResultTable(OverSeaFlightTableHeader)
+		for _, leg := range itinerary.Legs {
+			// 起飞地和到达地
+			departureCityName := fmt.Sprintf("%s-%s-%s(%s)", leg.Departure.CountryName, leg.Departure.CityName, leg.Departure.Name, leg.Departure.Terminal)
+			arrivalCityName := fmt.Sprintf("%s-%s-%s(%s)", leg.Arrival.CountryName, leg.Arrival.CityName, leg.Arrival.Name, leg.Arrival.Terminal)
+			// 转机时间
+			transferTime := durationDisplayString(leg.TransferDuration)
+			if leg.TransferDuration == 0 {
+				transferTime = "-"
+			}
+			// 写入表格数据
+			row := []string{
+				leg.FlightNumber, leg.AirlineName, leg.AircraftName, departureCityName, timeToString(leg.DepartureTime),
+				arrivalCityName, timeToString(leg.ArrivalTime), durationDisplayString(leg.Duration), transferTime,
+			}
+			table.Append(row)
+		}
+		footer := make([]string, len(OverSeaFlightTableFooter))
+		copy(footer, OverSeaFlightTableFooter)
+		footer[3] = fmt.Sprintf("当前舱位: %s", cabinName)
+		if len(itinerary.Fares) > 0 {
+			footer[4] = fmt.Sprintf("最低价格: %d 元", itinerary.Fares[0].TotalPrice())
+		}
+		// 渲染表格
+		table.SetFooter(append(footer, durationDisplayString(itinerary.Duration), ""))
+		table.Render()
+	}
+}
+
+// 渲染航班号信息表格
+func renderFlightInfoTable(statuses []flightgo.FlightStatus) {
+	table := newResultTable(FlightNumberInfoTableHeader)
+	for _, status := range statuses {
+		// 每一行的数据
+		row := []string{
+			status.Status,
+			status.FlightNumber,
+			status.Departure.Name,
+			status.Arrival.Name,
+			timeToString(status.ScheduledDepartureTime),
+			timeToString(status.ActualDepartureTime),
+			timeToString(status.ScheduledArrivalTime),
+			timeToString(status.ActualArrivalTime),
+			status.AircraftType,
+			status.AircraftNumber,
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
+
+// 渲染机场进出港表格（changed 中的航班高亮展示）
+func renderAirportInfoTable(depOrArr string, entries []flightgo.BoardEntry, changed map[string]bool) {
+	header := AirportInfoDepTableHeader
+	if depOrArr == "arr" {
+		header = AirportInfoArrTableHeader
+	}
+	// 合并了多个机场的航班时增加机场列
+	localAirports := make(map[string]bool)
+	for _, entry := range entries {
+		localAirports[entry.LocalEnd().Name] = true
+	}
+	multiAirport := len(localAirports) > 1
+	if multiAirport {
+		header = append([]string{AirportInfoAirportHeader}, header...)
+	}
+	table := newResultTable(header)
+	// 高亮的颜色代码会被计入自动换行的长度, 关闭自动换行
+	table.SetAutoWrapText(len(changed) == 0)
+	for _, entry := range entries {
+		// 出港展示目的地, 进港展示出发地
+		place := entry.Arrival
+		if depOrArr == "arr" {
+			place = entry.Departure
+		}
+		// 实际时间为空时展示预计时间
+		actualTime := entry.ActualTime
+		if actualTime.IsZero() {
+			actualTime = entry.EstimatedTime
+		}
+		row := []string{
+			entry.FlightNumber,
+			entry.AircraftType,
+			place.CityName,
+			place.Name,
+			timeToString(entry.ScheduledTime),
+			timeToString(actualTime),
+			entry.Status,
+		}
+		if multiAirport {
+			row = append([]string{entry.LocalEnd().Name}, row...)
+		}
+		if changed[entry.Key()] {
+			for i, cell := range row {
+				row[i] = fmt.Sprintf(BoardChangedCellFormat, cell)
+			}
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
+
+// 渲染航班状态码表格
+func renderFlightStatusCodeTable(entries []FlightStatusCodeEntry) {
+	table := newResultTable(FlightStatusCodeTableHeader)
+	for _, entry := range entries {
+		lastObservedAt := ""
+		if entry.LastObservedAt != nil {
+			lastObservedAt = entry.LastObservedAt.In(flightgo.ChinaLocation).Format("2006-01-02 15:04")
+		}
+		table.Append([]string{
+			strconv.FormatInt(entry.Code, 10),
+			entry.Name,
+			fmt.Sprintf("%s (%s)", flightgo.FlightPhaseNames[entry.Phase], entry.Phase),
+			statusCodeSourceNames[entry.Source],
+			strconv.Itoa(entry.Observations),
+			lastObservedAt,
+			entry.LastFlightNumber,
+		})
+	}
+	table.Render()
+	if unmapped := unmappedFlightPhases(entries); len(unmapped) > 0 {
+		names := make([]string, 0, len(unmapped))
+		for _, phase := range unmapped {
+			names = append(names, fmt.Sprintf("%s (%s)", flightgo.FlightPhaseNames[phase], phase))
+		}
+		fmt.Printf("以下阶段没有状态码映射, 需要在配置文件的 flightStatusCodes 中补充后才会出现: %s\n", strings.Join(names, "、"))
+	}
+}
+
+// 渲染未知状态码日志表格
+func renderUnknownFlightStatusTable(unknowns []flightgo.UnknownFlightStatus) {
+	table := newResultTable(UnknownFlightStatusTableHeader)
+	for _, unknown := range unknowns {
+		table.Append([]string{
+			unknown.ObservedAt.In(flightgo.ChinaLocation).Format("2006-01-02 15:04"),
+			unknown.Provider,
+			strconv.FormatInt(unknown.Code, 10),
+			unknown.FlightNumber,
+			unknown.Departure,
+			unknown.Arrival,
+		})
+	}
+	table.Render()
+}
+
+// 渲染城市数据表格
+func renderCityTable(cities []flightgo.City) {
+	table := newResultTable(CityTableHeader)
+	for _, city := range cities {
+		table.Append([]string{city.Name, city.Code, airportCodesString(city.Airports)})
+	}
+	table.Render()
+}
+
+// 渲染进出港航班准点率统计（机场、航空公司和准点率最低的航线各一张表格）
+func renderBoardStatsTable(report BoardStatsReport) {
+	if report.Flights == 0 {
+		fmt.Println("没有进出港航班记录")
+		return
+	}
+	fmt.Printf("共 %d 次查询, %d 个航班（计划时间: %s 至 %s）; 实际时间晚于计划时间不超过 %d 分钟视为准点, 取消视为不准点\n",
+		report.Polls, report.Flights, report.From.In(flightgo.ChinaLocation).Format("2006-01-02 15:04"),
+		report.To.In(flightgo.ChinaLocation).Format("2006-01-02 15:04"), int64(boardOnTimeThreshold/time.Minute))
+	sections := []struct {
+		title string
+		stats []BoardStats
+	}{
+		{"机场", report.Airports},
+		{"航空公司", report.Airlines},
+		{"航线", report.WorstRoutes},
+	}
+	for _, section := range sections {
+		if len(section.stats) == 0 {
+			continue
+		}
+		if section.title == "航线" {
+			fmt.Println("\n准点率最低的航线:")
+		} else {
+			fmt.Printf("\n按%s统计（准点率从高到低）:\n", section.title)
+		}
+		table := newResultTable(append([]string{section.title}, BoardStatsTableHeader...))
+		for _, stats := range section.stats {
+			onTimePercent, averageDelay, p90Delay := "-", "-", "-"
+			if stats.hasResult() {
+				onTimePercent = fmt.Sprintf("%.1f%%", stats.OnTimePercent)
+			}
+			if stats.Completed > 0 {
+				averageDelay = fmt.Sprintf("%.1f 分钟", stats.AverageDelay)
+				p90Delay = fmt.Sprintf("%d 分钟", stats.P90Delay)
+			}
+			table.Append([]string{
+				stats.Name,
+				strconv.Itoa(stats.Flights),
+				strconv.Itoa(stats.Completed),
+				strconv.Itoa(stats.OnTime),
+				onTimePercent,
+				averageDelay,
+				p90Delay,
+				strconv.Itoa(stats.Cancelled),
+			})
+		}
+		table.Render()
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+const (
+	// 默认的航班动态查询间隔
+	defaultTrackInterval = 2 * time.Minute
+	// 连续查询失败时最多延长到查询间隔的倍数
+	trackMaxIntervalFactor = 8
+	// 航班动态查询日期格式
+	trackDateLayout string = "20060102"
+)
+
+// 航班动态变化类型
+const (
+	TrackEventInitial        string = "initial"
+	TrackEventStatus         string = "status"
+	TrackEventDeparted       string = "departed"
+	TrackEventArrived        string = "arrived"
+	TrackEventDiverted       string = "diverted"
+	TrackEventScheduleChange string = "schedule"
+	TrackEventFinished       string = "finished"
+)
+
+// 一次航班动态变化
+type FlightStatusChange struct {
+	ObservedAt   time.Time `json:"observedAt"`
+	Event        string    `json:"event"`
+	FlightNumber string    `json:"flightNumber"`
+	Departure    string    `json:"departure"`
+	Arrival      string    `json:"arrival"`
+	// 变化前后的状态（状态变化时）
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	Status         string `json:"status"`
+	Message        string `json:"message"`
+}
+
+// 航段的唯一标识（经停航班每个航段一条动态）
+func trackLegKey(status flightgo.FlightStatus) string {
+	return fmt.Sprintf("%s|%s|%s", status.FlightNumber, status.Departure.Name, status.Arrival.Name)
+}
+
+// 航段名称（例如: 北京首都 → 上海虹桥）
+func trackLegName(departure, arrival string) string {
+	return fmt.Sprintf("%s → %s", departure, arrival)
+}
+
+// 状态展示名称（没有状态名称时展示为未知状态码）
+func trackStatusName(status flightgo.FlightStatus) string {
+	if status.Status != "" {
+		return status.Status
+	}
+	return flightgo.UnknownFlightStatusName(status.StatusCode)
+}
+
+// 时间展示（北京时间, 只保留时分）
+func trackClock(t flightgo.DateTime) string {
+	if t.IsZero() {
+		return "--:--"
+	}
+	return t.In(flightgo.ChinaLocation).Format("15:04")
+}
+
+// 延误展示（不超过 1 分钟时不展示）
+func trackDelayString(delay time.Duration) string {
+	if delay < time.Minute {
+		return ""
+	}
+	return fmt.Sprintf(", 延误 %d 分钟", int64(delay/time.Minute))
+}
+
+// 只保留计划起飞日期（北京时间）为查询日期的航段（接口会同时返回前后几天的航班, 没有匹配的航段时返回错误）
+func selectTrackedStatuses(statuses []flightgo.FlightStatus, date string) ([]flightgo.FlightStatus, error) {
+	day, err := time.ParseInLocation(trackDateLayout, date, flightgo.ChinaLocation)
+	if err != nil {
+		return nil, flightgo.NewInvalidArgumentError("航班动态日期格式错误: %s（格式: YYYYMMDD）", date)
+	}
+	selected := make([]flightgo.FlightStatus, 0, len(statuses))
+	for _, status := range statuses {
+		departure := status.ScheduledDepartureTime.In(flightgo.ChinaLocation)
+		if departure.Year() == day.Year() && departure.YearDay() == day.YearDay() {
+			selected = append(selected, status)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, flightgo.NewInvalidArgumentError("接口返回的 %d 个航段都不是计划在 %s 起飞的", len(statuses), day.Format("2006-01-02"))
+	}
+	return selected, nil
+}
+
+// 全部航段都已到达、取消或备降/返航后落地
+func flightStatusesFinal(statuses []flightgo.FlightStatus) bool {
+	for _, status := range statuses {
+		if !status.Final() {
+			return false
+		}
+	}
+	return len(statuses) > 0
+}
+
+// 比较前后两次的航班动态, 返回发生的变化（previous 为空时返回每个航段的当前状态）
+func diffFlightStatuses(previous map[string]flightgo.FlightStatus, current []flightgo.FlightStatus, now time.Time) []FlightStatusChange {
+	changes := make([]FlightStatusChange, 0)
+	for _, status := range current {
+		newChange := func(event, message string) FlightStatusChange {
+			return FlightStatusChange{
+				ObservedAt:   now,
+				Event:        event,
+				FlightNumber: status.FlightNumber,
+				Departure:    status.Departure.Name,
+				Arrival:      status.Arrival.Name,
+				Status:       trackStatusName(status),
+				Message:      message,
+			}
+		}
+		last, ok := previous[trackLegKey(status)]
+		if !ok {
+			message := fmt.Sprintf("当前状态: %s, 计划 %s 起飞, %s 到达",
+				trackStatusName(status), trackClock(status.ScheduledDepartureTime), trackClock(status.ScheduledArrivalTime))
+			if !status.ActualDepartureTime.IsZero() {
+				message += fmt.Sprintf("; 实际 %s 起飞%s", trackClock(status.ActualDepartureTime), trackDelayString(status.DepartureDelay()))
+			}
+			if !status.ActualArrivalTime.IsZero() {
+				message += fmt.Sprintf(", %s 到达%s", trackClock(status.ActualArrivalTime), trackDelayString(status.ArrivalDelay()))
+			}
+			changes = append(changes, newChange(TrackEventInitial, message))
+			continue
+		}
+		if last.StatusCode != status.StatusCode || last.Status != status.Status {
+			event, message := TrackEventStatus, fmt.Sprintf("%s → %s", trackStatusName(last), trackStatusName(status))
+			if status.Diverted() && !last.Diverted() {
+				event, message = TrackEventDiverted, fmt.Sprintf("航班%s: %s", trackStatusName(status), message)
+			}
+			change := newChange(event, message)
+			change.PreviousStatus = trackStatusName(last)
+			changes = append(changes, change)
+		}
+		scheduleChanges := make([]string, 0, 2)
+		if !last.ScheduledDepartureTime.Equal(status.ScheduledDepartureTime.Time) {
+			scheduleChanges = append(scheduleChanges, fmt.Sprintf("起飞 %s → %s", trackClock(last.ScheduledDepartureTime), trackClock(status.ScheduledDepartureTime)))
+		}
+		if !last.ScheduledArrivalTime.Equal(status.ScheduledArrivalTime.Time) {
+			scheduleChanges = append(scheduleChanges, fmt.Sprintf("到达 %s → %s", trackClock(last.ScheduledArrivalTime), trackClock(status.ScheduledArrivalTime)))
+		}
+		if len(scheduleChanges) > 0 {
+			changes = append(changes, newChange(TrackEventScheduleChange, "计划时间变更: "+strings.Join(scheduleChanges, ", ")))
+		}
+		if last.ActualDepartureTime.IsZero() && !status.ActualDepartureTime.IsZero() {
+			changes = append(changes, newChange(TrackEventDeparted, fmt.Sprintf("已于 %s 起飞%s",
+				trackClock(status.ActualDepartureTime), trackDelayString(status.DepartureDelay()))))
+		}
+		if last.ActualArrivalTime.IsZero() && !status.ActualArrivalTime.IsZero() {
+			message := fmt.Sprintf("已于 %s 到达%s", trackClock(status.ActualArrivalTime), trackDelayString(status.ArrivalDelay()))
+			if status.Diverted() {
+				message = fmt.Sprintf("%s后已于 %s 落地", trackStatusName(status), trackClock(status.ActualArrivalTime))
+			}
+			changes = append(changes, newChange(TrackEventArrived, message))
+		}
+	}
+	return changes
+}
+
+// 输出航班动态变化（table 输出一行文字, ndjson 输出一行 JSON）
+func printFlightStatusChange(change FlightStatusChange) {
+	legName := trackLegName(change.Departure, change.Arrival)
+	logger.Infof("[Flight-Go]%s %s %s", change.FlightNumber, legName, change.Message)
+	if outputFormat != OutputTable {
+		_ = writeNDJSON(os.Stdout, []interface{}{change})
+		return
+	}
+	fmt.Printf("[%s] %s %s: %s\n", change.ObservedAt.Format("15:04:05"), change.FlightNumber, legName, change.Message)
+}
+
+// 持续跟踪航班动态, 全部航段到达、取消或备降/返航后落地时返回
+func runTrack(ctx context.Context, client *flightgo.Client, flightNumber, date string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultTrackInterval
+	}
+	previous := make(map[string]flightgo.FlightStatus)
+	failures := 0
+	for {
+		statuses, err := client.FlightStatus(ctx, flightNumber, date)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && len(previous) == 0:
+			// 第一次查询失败时直接返回（例如航班号错误）
+			return err
+		case err != nil:
+			failures++
+			logger.Errorf("[Flight-Go]查询航班 %s 动态失败, 错误原因: %v", flightNumber, err)
+		default:
+			failures = 0
+			if len(statuses) == 0 {
+				return flightgo.NewInvalidArgumentError("没有查询到航班 %s 在 %s 的动态", flightNumber, date)
+			}
+			if statuses, err = selectTrackedStatuses(statuses, date); err != nil {
+				return err
+			}
+			for _, change := range diffFlightStatuses(previous, statuses, time.Now()) {
+				printFlightStatusChange(change)
+			}
+			for _, status := range statuses {
+				previous[trackLegKey(status)] = status
+			}
+			if flightStatusesFinal(statuses) {
+				last := statuses[len(statuses)-1]
+				message := "航班已到达, 停止跟踪"
+				switch {
+				case last.Cancelled():
+					message = "航班已取消, 停止跟踪"
+				case last.Diverted():
+					message = fmt.Sprintf("航班%s后已落地, 停止跟踪", trackStatusName(last))
+				}
+				printFlightStatusChange(FlightStatusChange{
+					ObservedAt:   time.Now(),
+					Event:        TrackEventFinished,
+					FlightNumber: last.FlightNumber,
+					Departure:    statuses[0].Departure.Name,
+					Arrival:      last.Arrival.Name,
+					Status:       trackStatusName(last),
+					Message:      message,
+				})
+				return nil
+			}
+		}
+		next := nextWatchInterval(interval, interval*trackMaxIntervalFactor, failures)
+		logger.Debugf("[Flight-Go]%s 后再次查询航班 %s 动态", next.Round(time.Second), flightNumber)
+		if sleepWithContext(ctx, next) != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+func testTrackTime(day, hour, min int) flightgo.DateTime {
+	return flightgo.DateTime{Time: time.Date(2019, 11, day, hour, min, 0, 0, flightgo.ChinaLocation)}
+}
+
+func testFlightStatus(day int) flightgo.FlightStatus {
+	return flightgo.FlightStatus{
+		FlightNumber:           "CA1501",
+		StatusCode:             flightgo.FlightStatusScheduled,
+		Status:                 "计划",
+		Phase:                  flightgo.FlightPhaseScheduled,
+		Departure:              flightgo.Airport{Name: "北京首都"},
+		Arrival:                flightgo.Airport{Name: "上海虹桥"},
+		ScheduledDepartureTime: testTrackTime(day, 8, 30),
+		ScheduledArrivalTime:   testTrackTime(day, 10, 40),
+	}
+}
+
+func TestSelectTrackedStatuses(t *testing.T) {
+	statuses := []flightgo.FlightStatus{testFlightStatus(14), testFlightStatus(15), testFlightStatus(16)}
+	tests := []struct {
+		date string
+		days []int
+	}{
+		{"20191115", []int{15}},
+		{"20191116", []int{16}},
+		// 没有匹配的日期或日期格式错误时返回错误
+		{"20191120", nil},
+		{"2019-11-15", nil},
+	}
+	for _, tt := range tests {
+		selected, err := selectTrackedStatuses(statuses, tt.date)
+		if (err == nil) != (tt.days != nil) {
+			t.Errorf("selectTrackedStatuses(%s): %v, 期望合法: %v", tt.date, err, tt.days != nil)
+			continue
+		}
+		if err != nil && !flightgo.IsErrorKind(err, flightgo.ErrorInvalidArgument) {
+			t.Errorf("selectTrackedStatuses(%s): %v, 期望参数错误", tt.date, err)
+		}
+		days := make([]int, 0, len(selected))
+		for _, status := range selected {
+			days = append(days, status.ScheduledDepartureTime.Day())
+		}
+		if len(days) != len(tt.days) {
+			t.Errorf("selectTrackedStatuses(%s): %v, 期望 %v", tt.date, days, tt.days)
+			continue
+		}
+		for i := range days {
+			if days[i] != tt.days[i] {
+				t.Errorf("selectTrackedStatuses(%s): %v, 期望 %v", tt.date, days, tt.days)
+				break
+			}
+		}
+	}
+}
+
+func TestFlightStatusesFinal(t *testing.T) {
+	arrived := testFlightStatus(15)
+	arrived.ActualArrivalTime = testTrackTime(15, 10, 50)
+	cancelled := testFlightStatus(15)
+	cancelled.Status, cancelled.Phase = "取消", flightgo.FlightPhaseCancelled
+	diverted := testFlightStatus(15)
+	diverted.Status, diverted.Phase = "备降", flightgo.FlightPhaseDiverted
+	divertedLanded := diverted
+	divertedLanded.ActualArrivalTime = testTrackTime(15, 11, 20)
+	tests := []struct {
+		name     string
+		statuses []flightgo.FlightStatus
+		want     bool
+	}{
+		{"没有航段", nil, false},
+		{"未起飞", []flightgo.FlightStatus{testFlightStatus(15)}, false},
+		{"已到达", []flightgo.FlightStatus{arrived}, true},
+		{"已取消", []flightgo.FlightStatus{cancelled}, true},
+		{"备降中", []flightgo.FlightStatus{diverted}, false},
+		{"备降后落地", []flightgo.FlightStatus{divertedLanded}, true},
+		{"经停航班只到达第一段", []flightgo.FlightStatus{arrived, testFlightStatus(15)}, false},
+	}
+	for _, tt := range tests {
+		if got := flightStatusesFinal(tt.statuses); got != tt.want {
+			t.Errorf("%s: %v, 期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiffFlightStatuses(t *testing.T) {
+	now := time.Date(2019, 11, 15, 9, 0, 0, 0, flightgo.ChinaLocation)
+	scheduled := testFlightStatus(15)
+	departed := scheduled
+	departed.StatusCode, departed.Status, departed.Phase = flightgo.FlightStatusDeparted, "起飞", flightgo.FlightPhaseDeparted
+	departed.ActualDepartureTime = testTrackTime(15, 8, 55)
+	arrived := departed
+	arrived.StatusCode, arrived.Status, arrived.Phase = flightgo.FlightStatusArrived, "到达", flightgo.FlightPhaseLanded
+	arrived.ActualArrivalTime = testTrackTime(15, 10, 45)
+	diverted := departed
+	diverted.StatusCode, diverted.Status, diverted.Phase = 3, "备降", flightgo.FlightPhaseDiverted
+	divertedLanded := diverted
+	divertedLanded.ActualArrivalTime = testTrackTime(15, 11, 20)
+	rescheduled := scheduled
+	rescheduled.ScheduledDepartureTime = testTrackTime(15, 9, 30)
+	tests := []struct {
+		name     string
+		previous []flightgo.FlightStatus
+		current  flightgo.FlightStatus
+		// 每个变化的类型和说明
+		changes []string
+	}{
+		{"第一次查询", nil, departed, []string{
+			"initial 当前状态: 起飞, 计划 08:30 起飞, 10:40 到达; 实际 08:55 起飞, 延误 25 分钟",
+		}},
+		{"没有变化", []flightgo.FlightStatus{scheduled}, scheduled, nil},
+		{"起飞", []flightgo.FlightStatus{scheduled}, departed, []string{
+			"status 计划 → 起飞",
+			"departed 已于 08:55 起飞, 延误 25 分钟",
+		}},
+		{"到达", []flightgo.FlightStatus{departed}, arrived, []string{
+			"status 起飞 → 到达",
+			"arrived 已于 10:45 到达, 延误 5 分钟",
+		}},
+		{"备降", []flightgo.FlightStatus{departed}, diverted, []string{
+			"diverted 航班备降: 起飞 → 备降",
+		}},
+		{"备降后落地", []flightgo.FlightStatus{diverted}, divertedLanded, []string{
+			"arrived 备降后已于 11:20 落地",
+		}},
+		{"计划时间变更", []flightgo.FlightStatus{scheduled}, rescheduled, []string{
+			"schedule 计划时间变更: 起飞 08:30 → 09:30",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := make(map[string]flightgo.FlightStatus)
+			for _, status := range tt.previous {
+				previous[trackLegKey(status)] = status
+			}
+			changes := diffFlightStatuses(previous, []flightgo.FlightStatus{tt.current}, now)
+			got := make([]string, 0, len(changes))
+			for _, change := range changes {
+				got = append(got, change.Event+" "+change.Message)
+				if change.FlightNumber != "CA1501" || !change.ObservedAt.Equal(now) {
+					t.Errorf("变化: %+v", change)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.changes, "\n") {
+				t.Errorf("变化:\n%s\n期望:\n%s", strings.Join(got, "\n"), strings.Join(tt.changes, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+const (
+	defaultWatchInterval    = time.Minute * 30
+	defaultWatchMaxInterval = time.Hour * 6
+	defaultWatchRouteDelay  = time.Second * 3
+	watchStateFileName      = "watch_state.json"
+)
+
+// 监控的航线
+type WatchRoute struct {
+	// 名称（用于提醒信息, 默认: 出发地-到达地 日期）
+	Name string `json:"name,omitempty"`
+	// 行程类型（domestic: 国内; international: 国际; 默认: domestic）
+	Type      string `json:"type,omitempty"`
+	Departure string `json:"departure"`
+	Arrival   string `json:"arrival"`
+	// 出发日期（格式: YYYY-MM-DD）
+	Date string `json:"date"`
+	// 舱位等级（默认: 经济舱）
+	Cabin string `json:"cabin,omitempty"`
+	// 最低价格低于该值时提醒（元, 0 表示不检查）
+	Below int64 `json:"below,omitempty"`
+	// 最低价格比上次下降超过该百分比时提醒（0 表示不检查）
+	DropPercent float64 `json:"dropPercent,omitempty"`
+}
+
+// 监控配置文件
+type WatchConfig struct {
+	// 轮询间隔（默认: 30m）
+	Interval string `json:"interval,omitempty"`
+	// 查询失败时退避的最大间隔（默认: 6h）
+	MaxInterval string `json:"maxInterval,omitempty"`
+	// 同一轮中两次查询之间的间隔（默认: 3s）
+	RouteDelay string `json:"routeDelay,omitempty"`
+	// 状态文件路径（默认: 用户缓存目录下的 flight-go/watch_state.json）
+	StatePath string       `json:"statePath,omitempty"`
+	Routes    []WatchRoute `json:"routes"`
+}
+
+// 航线的监控状态
+type WatchRouteState struct {
+	LowestPrice   int64  `json:"lowestPrice"`
+	FlightNumbers string `json:"flightNumbers,omitempty"`
+	// 上次提醒时的价格（价格没有继续下降时不重复提醒, 回升到阈值以上后清零）
+	LastAlertPrice int64     `json:"lastAlertPrice,omitempty"`
+	CheckedAt      time.Time `json:"checkedAt"`
+}
+
+// 监控状态（重启后继续使用）
+type WatchState struct {
+	Routes map[string]*WatchRouteState `json:"routes"`
+}
+
+// 价格提醒
+type WatchAlert struct {
+	Route         string    `json:"route"`
+	Cabin         string    `json:"cabin"`
+	Price         int64     `json:"price"`
+	PreviousPrice int64     `json:"previousPrice,omitempty"`
+	FlightNumbers string    `json:"flightNumbers"`
+	Reason        string    `json:"reason"`
+	Time          time.Time `json:"time"`
+}
+
+// 航线的唯一标识（用于保存状态）
+func (r WatchRoute) key() string {
+	return strings.Join([]string{r.Type, r.Departure, r.Arrival, r.Date, r.Cabin}, "|")
+}
+
+// 航线名称
+func (r WatchRoute) displayName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s-%s %s", r.Departure, r.Arrival, r.Date)
+}
+
+// 解析时间间隔（为空时使用默认值）
+func parseWatchDuration(name, value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, flightgo.NewInvalidArgumentError("监控配置 %s 格式错误: %s（例如: 30m, 1h）", name, value)
+	}
+	return duration, nil
+}
+
+// 读取监控配置
+func loadWatchConfig(path string) (*WatchConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, flightgo.NewInvalidArgumentError("读取监控配置失败: %v", err)
+	}
+	watchConfig := &WatchConfig{}
+	if err := json.Unmarshal(data, watchConfig); err != nil {
+		return nil, flightgo.NewInvalidArgumentError("监控配置格式错误: %s, %v", path, err)
+	}
+	if len(watchConfig.Routes) == 0 {
+		return nil, flightgo.NewInvalidArgumentError("监控配置中没有航线: %s", path)
+	}
+	for i := range watchConfig.Routes {
+		route := &watchConfig.Routes[i]
+		if route.Type == "" {
+			route.Type = MarketDomestic
+		}
+		if route.Cabin == "" {
+			route.Cabin = flightgo.EconomyClassName
+		}
+		if route.Type != MarketDomestic && route.Type != MarketInternational {
+			return nil, flightgo.NewInvalidArgumentError("第 %d 条航线的类型错误: %s（domestic 或 international）", i+1, route.Type)
+		}
+		if route.Departure == "" || route.Arrival == "" || route.Date == "" {
+			return nil, flightgo.NewInvalidArgumentError("第 %d 条航线缺少出发地、到达地或日期", i+1)
+		}
+		if route.Below <= 0 && route.DropPercent <= 0 {
+			return nil, flightgo.NewInvalidArgumentError("第 %d 条航线需要设置 below 或 dropPercent", i+1)
+		}
+	}
+	return watchConfig, nil
+}
+
+// 默认状态文件路径
+func defaultWatchStatePath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return watchStateFileName
+	}
+	return filepath.Join(cacheDir, "flight-go", watchStateFileName)
+}
+
+// 读取监控状态（文件不存在时返回空状态）
+func readWatchState(path string) (*WatchState, error) {
+	state := &WatchState{Routes: make(map[string]*WatchRouteState)}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("监控状态文件格式错误: %s, %v", path, err)
+	}
+	if state.Routes == nil {
+		state.Routes = make(map[string]*WatchRouteState)
+	}
+	return state, nil
+}
+
+// 保存监控状态
+func writeWatchState(path string, state *WatchState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再重命名, 避免中断时损坏状态文件
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// 查询航线当前舱位等级的最低价格
+func searchWatchRoute(ctx context.Context, client *flightgo.Client, route WatchRoute) (int64, string, error) {
+	var itineraries []flightgo.Itinerary
+	var err error
+	if route.Type == MarketInternational {
+		itineraries, err = client.SearchInternational(ctx, flightgo.InternationalSearchRequest{
+			Departure: route.Departure,
+			Arrival:   route.Arrival,
+			Date:      route.Date,
+			Cabin:     route.Cabin,
+		})
+	} else {
+		itineraries, err = client.SearchDomestic(ctx, flightgo.DomesticSearchRequest{
+			Departure: route.Departure,
+			Arrival:   route.Arrival,
+			Date:      route.Date,
+		})
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	recordFareHistory(route.Type, route.Departure, route.Arrival, route.Date, itineraries)
+	var lowestPrice int64
+	var flightNumbers string
+	for _, itinerary := range itineraries {
+		// 国际航班的票价即为查询的舱位等级
+		cabinName := route.Cabin
+		if route.Type == MarketInternational && len(itinerary.Fares) > 0 {
+			cabinName = itinerary.Fares[0].Cabin.Name
+		}
+		fare, ok := itinerary.LowestFare(cabinName)
+		if ok && (lowestPrice == 0 || fare.TotalPrice() < lowestPrice) {
+			lowestPrice = fare.TotalPrice()
+			flightNumbers = itinerary.FlightNumbers()
+		}
+	}
+	return lowestPrice, flightNumbers, nil
+}
+
+// 根据上次的状态判断是否需要提醒
+func checkWatchAlert(route WatchRoute, previous *WatchRouteState, price int64, flightNumbers string) *WatchAlert {
+	if price == 0 {
+		return nil
+	}
+	alert := &WatchAlert{
+		Route:         route.displayName(),
+		Cabin:         route.Cabin,
+		Price:         price,
+		FlightNumbers: flightNumbers,
+		Time:          time.Now(),
+	}
+	if previous != nil && previous.LowestPrice > 0 {
+		alert.PreviousPrice = previous.LowestPrice
+	}
+	// 已经提醒过且价格没有继续下降时不再提醒
+	if previous != nil && previous.LastAlertPrice > 0 && price >= previous.LastAlertPrice {
+		return nil
+	}
+	if route.Below > 0 && price < route.Below {
+		alert.Reason = fmt.Sprintf("低于 %d 元", route.Below)
+		return alert
+	}
+	if route.DropPercent > 0 && alert.PreviousPrice > 0 {
+		drop := float64(alert.PreviousPrice-price) / float64(alert.PreviousPrice) * 100
+		if drop >= route.DropPercent {
+			alert.Reason = fmt.Sprintf("比上次下降 %.1f%%", drop)
+			return alert
+		}
+	}
+	return nil
+}
+
+// 提醒后价格回升到阈值以上时重新允许提醒（回到 below 及以上, 或比提醒时的价格上涨超过 dropPercent%）
+// 价格在提醒时的价格附近小幅波动时不重新提醒
+func watchAlertRearmed(route WatchRoute, previous *WatchRouteState, price int64) bool {
+	if previous == nil || previous.LastAlertPrice == 0 || price == 0 {
+		return false
+	}
+	if route.Below > 0 && price >= route.Below {
+		return true
+	}
+	if route.DropPercent > 0 {
+		rise := float64(price-previous.LastAlertPrice) / float64(previous.LastAlertPrice) * 100
+		return rise >= route.DropPercent
+	}
+	return false
+}
+
+// 输出提醒（table 格式输出一行文字, 其他格式每条提醒输出一行 JSON）
+func printWatchAlert(alert *WatchAlert) {
+	logger.Warnf("[Flight-Go]价格提醒: %s %s %d 元（%s）", alert.Route, alert.Cabin, alert.Price, alert.Reason)
+	if outputFormat != OutputTable {
+		_ = writeNDJSON(os.Stdout, []interface{}{alert})
+		return
+	}
+	previous := ""
+	if alert.PreviousPrice > 0 {
+		previous = fmt.Sprintf(", 上次 %d 元", alert.PreviousPrice)
+	}
+	fmt.Printf("价格提醒: %s %s 最低 %d 元（%s）, 航班 %s%s\n", alert.Route, alert.Cabin, alert.Price, alert.Reason, alert.FlightNumbers, previous)
+}
+
+// 查询一轮全部航线, 返回查询失败的数量和最后一个错误
+func pollWatchRoutes(ctx context.Context, client *flightgo.Client, watchConfig *WatchConfig, state *WatchState, routeDelay time.Duration) (int, error) {
+	failures := 0
+	var lastErr error
+	for i, route := range watchConfig.Routes {
+		if i > 0 && sleepWithContext(ctx, routeDelay) != nil {
+			return failures, lastErr
+		}
+		price, flightNumbers, err := searchWatchRoute(ctx, client, route)
+		if err != nil {
+			logger.Errorf("[Flight-Go]查询 %s 失败, 错误原因: %v", route.displayName(), err)
+			failures++
+			lastErr = err
+			continue
+		}
+		previous := state.Routes[route.key()]
+		if alert := checkWatchAlert(route, previous, price, flightNumbers); alert != nil {
+			printWatchAlert(alert)
+			previous = &WatchRouteState{LastAlertPrice: price}
+		} else if watchAlertRearmed(route, previous, price) {
+			previous.LastAlertPrice = 0
+		}
+		if previous == nil {
+			previous = &WatchRouteState{}
+		}
+		previous.CheckedAt = time.Now()
+		state.Routes[route.key()] = previous
+		if price == 0 {
+			// 没有查询到票价时保留上次的最低价, 作为下次计算降幅的基准
+			logger.Infof("[Flight-Go]%s %s 没有查询到票价", route.displayName(), route.Cabin)
+			continue
+		}
+		previous.LowestPrice = price
+		previous.FlightNumbers = flightNumbers
+		logger.Infof("[Flight-Go]%s %s 当前最低 %d 元", route.displayName(), route.Cabin, price)
+	}
+	return failures, lastErr
+}
+
+// 下一次轮询的间隔（连续失败时按指数退避, 并加入 ±10% 的随机抖动）
+func nextWatchInterval(interval, maxInterval time.Duration, consecutiveFailures int) time.Duration {
+	next := interval
+	for i := 0; i < consecutiveFailures && next < maxInterval; i++ {
+		next *= 2
+	}
+	if next > maxInterval {
+		next = maxInterval
+	}
+	jitter := time.Duration(float64(next) * 0.1 * (rand.Float64()*2 - 1))
+	return next + jitter
+}
+
+// 等待一段时间（context 取消时提前返回）
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// 持续监控（once 为 true 时只查询一轮）
+func runWatch(ctx context.Context, client *flightgo.Client, watchConfig *WatchConfig, statePath string, once bool) error {
+	interval, err := parseWatchDuration("interval", watchConfig.Interval, defaultWatchInterval)
+	if err != nil {
+		return err
+	}
+	maxInterval, err := parseWatchDuration("maxInterval", watchConfig.MaxInterval, defaultWatchMaxInterval)
+	if err != nil {
+		return err
+	}
+	routeDelay, err := parseWatchDuration("routeDelay", watchConfig.RouteDelay, defaultWatchRouteDelay)
+	if err != nil {
+		return err
+	}
+	state, err := readWatchState(statePath)
+	if err != nil {
+		return err
+	}
+	consecutiveFailures := 0
+	for {
+		failures, lastErr := pollWatchRoutes(ctx, client, watchConfig, state, routeDelay)
+		if err := writeWatchState(statePath, state); err != nil {
+			logger.Errorf("[Flight-Go]保存监控状态失败, 错误原因: %v", err)
+		}
+		if failures == len(watchConfig.Routes) {
+			consecutiveFailures++
+		} else {
+			consecutiveFailures = 0
+		}
+		if once || ctx.Err() != nil {
+			// 只查询一轮且全部失败时返回错误, 方便定时任务判断
+			if failures == len(watchConfig.Routes) {
+				return lastErr
+			}
+			return nil
+		}
+		wait := nextWatchInterval(interval, maxInterval, consecutiveFailures)
+		logger.Infof("[Flight-Go]%s 后进行下一次查询", wait.Round(time.Second))
+		if sleepWithContext(ctx, wait) != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sunhailin-Leo/Flight-Go/flightgo"
+)
+
+func TestCheckWatchAlert(t *testing.T) {
+	below := WatchRoute{Departure: "北京", Arrival: "上海", Date: "2019-11-15", Cabin: "经济舱", Below: 700}
+	drop := WatchRoute{Departure: "北京", Arrival: "上海", Date: "2019-11-15", Cabin: "经济舱", DropPercent: 10}
+	tests := []struct {
+		name     string
+		route    WatchRoute
+		previous *WatchRouteState
+		price    int64
+		// 期望的提醒原因（为空表示不提醒）
+		reason string
+	}{
+		{"低于阈值", below, nil, 650, "低于 700 元"},
+		{"不低于阈值", below, nil, 700, ""},
+		{"没有票价", below, nil, 0, ""},
+		{"已提醒且价格未继续下降", below, &WatchRouteState{LowestPrice: 650, LastAlertPrice: 650}, 650, ""},
+		{"已提醒后价格继续下降", below, &WatchRouteState{LowestPrice: 650, LastAlertPrice: 650}, 600, "低于 700 元"},
+		{"降幅达到百分比", drop, &WatchRouteState{LowestPrice: 1000}, 880, "比上次下降 12.0%"},
+		{"降幅不足", drop, &WatchRouteState{LowestPrice: 1000}, 950, ""},
+		{"首次查询没有上次价格", drop, nil, 500, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := checkWatchAlert(tt.route, tt.previous, tt.price, "MU5138")
+			if tt.reason == "" {
+				if alert != nil {
+					t.Errorf("不应提醒: %+v", alert)
+				}
+				return
+			}
+			if alert == nil || alert.Reason != tt.reason || alert.Price != tt.price || alert.FlightNumbers != "MU5138" {
+				t.Errorf("提醒: %+v, 期望原因 %s", alert, tt.reason)
+			}
+		})
+	}
+}
+
+func TestWatchAlertRearmed(t *testing.T) {
+	below := WatchRoute{Below: 700}
+	drop := WatchRoute{DropPercent: 10}
+	alerted := &WatchRouteState{LowestPrice: 650, LastAlertPrice: 650}
+	tests := []struct {
+		name     string
+		route    WatchRoute
+		previous *WatchRouteState
+		price    int64
+		want     bool
+	}{
+		{"没有提醒过", below, &WatchRouteState{LowestPrice: 800}, 900, false},
+		{"小幅回升仍低于阈值", below, alerted, 680, false},
+		{"回升到阈值", below, alerted, 700, true},
+		{"没有票价", below, alerted, 0, false},
+		{"涨幅不足", drop, alerted, 700, false},
+		{"涨幅达到百分比", drop, alerted, 720, true},
+	}
+	for _, tt := range tests {
+		if got := watchAlertRearmed(tt.route, tt.previous, tt.price); got != tt.want {
+			t.Errorf("%s: %v, 期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadWatchConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{"默认类型和舱位", `{"routes": [{"departure": "北京", "arrival": "上海", "date": "2019-11-15", "below": 700}]}`, true},
+		{"没有航线", `{"routes": []}`, false},
+		{"类型错误", `{"routes": [{"type": "train", "departure": "北京", "arrival": "上海", "date": "2019-11-15", "below": 700}]}`, false},
+		{"缺少日期", `{"routes": [{"departure": "北京", "arrival": "上海", "below": 700}]}`, false},
+		{"没有提醒条件", `{"routes": [{"departure": "北京", "arrival": "上海", "date": "2019-11-15"}]}`, false},
+		{"格式错误", `{"routes": `, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watchConfig, err := loadWatchConfig(writeTestConfig(t, tt.content))
+			if (err == nil) != tt.valid {
+				t.Fatalf("加载监控配置: %v, 期望合法: %v", err, tt.valid)
+			}
+			if tt.valid && (watchConfig.Routes[0].Type != MarketDomestic || watchConfig.Routes[0].Cabin != "经济舱") {
+				t.Errorf("默认值: %+v", watchConfig.Routes[0])
+			}
+		})
+	}
+}
+
+func TestNextWatchInterval(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, time.Minute * 30},
+		{1, time.Hour},
+		{2, time.Hour * 2},
+		// 不超过最大间隔
+		{10, time.Hour * 6},
+	}
+	for _, tt := range tests {
+		// 随机抖动在 ±10% 以内
+		next := nextWatchInterval(time.Minute*30, time.Hour*6, tt.failures)
+		if next < tt.want*9/10 || next > tt.want*11/10 {
+			t.Errorf("连续失败 %d 次的间隔: %s, 期望约 %s", tt.failures, next, tt.want)
+		}
+	}
+}
+
+func TestWatchState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flight-go", watchStateFileName)
+	// 状态文件不存在时返回空状态
+	state, err := readWatchState(path)
+	if err != nil || len(state.Routes) != 0 {
+		t.Fatalf("读取空状态: %+v（%v）", state, err)
+	}
+	state.Routes["domestic|北京|上海|2019-11-15|经济舱"] = &WatchRouteState{LowestPrice: 650, FlightNumbers: "MU5138", LastAlertPrice: 650}
+	if err := writeWatchState(path, state); err != nil {
+		t.Fatalf("保存状态失败: %v", err)
+	}
+	loaded, err := readWatchState(path)
+	if err != nil {
+		t.Fatalf("读取状态失败: %v", err)
+	}
+	if route := loaded.Routes["domestic|北京|上海|2019-11-15|经济舱"]; route == nil || route.LowestPrice != 650 || route.LastAlertPrice != 650 {
+		t.Errorf("状态: %+v", loaded.Routes)
+	}
+}
+
+func TestPollWatchRoutes(t *testing.T) {
+	t.Setenv(historyDirEnv, t.TempDir())
+	products := readCassetteBody(t, "schedule/db7cc40becea0ca6-001.json")
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(products))
+	}))
+	t.Cleanup(upstream.Close)
+	client := flightgo.New(
+		flightgo.WithHTTPClient(upstream.Client()),
+		flightgo.WithEndpoints(flightgo.Endpoints{CtripBaseURL: upstream.URL}),
+	)
+	economy := WatchRoute{Type: MarketDomestic, Departure: "北京", Arrival: "上海", Date: "2019-11-15", Cabin: "经济舱", Below: 700}
+	// 录制的结果中没有公务舱的票价
+	premium := WatchRoute{Type: MarketDomestic, Departure: "北京", Arrival: "上海", Date: "2019-11-15", Cabin: "公务舱", DropPercent: 10}
+	state := &WatchState{Routes: map[string]*WatchRouteState{
+		premium.key(): {LowestPrice: 1200, FlightNumbers: "CA1501"},
+	}}
+	failures, err := pollWatchRoutes(context.Background(), client, &WatchConfig{Routes: []WatchRoute{economy, premium}}, state, 0)
+	if failures != 0 || err != nil {
+		t.Fatalf("查询失败: %d 次, %v", failures, err)
+	}
+	if route := state.Routes[economy.key()]; route == nil || route.LowestPrice != 650 || route.LastAlertPrice != 650 || route.FlightNumbers != "MU5138" {
+		t.Errorf("经济舱状态: %+v", route)
+	}
+	// 提醒后价格小幅回升但仍低于阈值时不重新提醒
+	state.Routes[economy.key()].LastAlertPrice = 640
+	if _, err := pollWatchRoutes(context.Background(), client, &WatchConfig{Routes: []WatchRoute{economy}}, state, 0); err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if route := state.Routes[economy.key()]; route.LastAlertPrice != 640 {
+		t.Errorf("上次提醒的价格: %d, 期望 640", route.LastAlertPrice)
+	}
+	// 没有查询到票价时保留上次的最低价
+	if route := state.Routes[premium.key()]; route.LowestPrice != 1200 || route.FlightNumbers != "CA1501" || route.CheckedAt.IsZero() {
+		t.Errorf("公务舱状态: %+v", route)
+	}
+}